@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	auditDesc = `
+This will check each existing version tag against the commits between it and its previous tag with the configured
+majorPattern and minorPattern. It reports:
+- not-semver: tags that are not semver compatible
+- skipped-version: tags that do not directly follow their previous tag (eg. 1.0.0 to 1.2.0)
+- under-bump: tags that bump less than their commits require (eg. a patch with a BREAKING CHANGE commit)
+- over-bump: tags that bump more than their commits require (eg. a minor with only fixes)
+
+Pre-release tags are only checked to be semver compatible and release tags are checked against their previous release tag.
+Moving from an initial development version 0.y.z to 1.0.0 is always allowed.
+
+The command exits with a non-zero status if any violation is found.
+`
+	auditExample = `
+# To audit the tags of the repository
+gsemver audit
+
+# To audit the tags in json
+gsemver audit --output json
+`
+)
+
+// newAuditCommands create the audit command
+func newAuditCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &auditOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "audit",
+		Short:        "Check existing tags against the commit conventions",
+		Long:         auditDesc,
+		Example:      auditExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addAuditFlags(cmd)
+
+	return cmd
+}
+
+// auditOptions type to represent the available options for the audit command
+// It extends GlobalOptions.
+type auditOptions struct {
+	*globalOptions
+	viperConfig config
+	// Output is the output format: text or json
+	Output string
+}
+
+func (o *auditOptions) addAuditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Output, "output", "o", outputText, "Output format: text or json")
+
+	o.Cmd = cmd
+}
+
+func (o *auditOptions) run() error {
+	log.Debug("Run audit command with configuration: %#v", o)
+
+	if o.Output != outputText && o.Output != outputJSON {
+		return errors.Errorf("unknown output format %q. Try 'text' or 'json'", o.Output)
+	}
+
+	strategy, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+	violations, err := strategy.Audit()
+	if err != nil {
+		return err
+	}
+
+	return writeAuditViolations(o.ioStreams.Out, o.Output, violations)
+}
+
+// writeAuditViolations writes the violations in the output format and returns an error if there is any violation
+func writeAuditViolations(out io.Writer, output string, violations []version.AuditViolation) error {
+	if output == outputJSON {
+		if err := writeJSON(out, violations); err != nil {
+			return err
+		}
+	} else {
+		for _, v := range violations {
+			fmt.Fprintln(out, v)
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.Errorf("%d tag(s) do not follow the commit conventions", len(violations))
+	}
+	return nil
+}
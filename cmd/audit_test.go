@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestWriteAuditViolations(t *testing.T) {
+	violations := []version.AuditViolation{
+		{Tag: "v1.2.0", PreviousTag: "v1.0.0", Type: version.AuditSkippedVersion, Message: "1.2.0 does not directly follow 1.0.0, expected 1.1.0"},
+		{Tag: "latest", Type: version.AuditNotSemver, Message: "'latest' is not a semver compatible version"},
+	}
+
+	testData := []struct {
+		output     string
+		violations []version.AuditViolation
+		expected   string
+		err        string
+	}{
+		{outputText, []version.AuditViolation{}, "", ""},
+		{outputJSON, []version.AuditViolation{}, "[]\n", ""},
+		{outputText, violations, `v1.2.0: skipped-version: 1.2.0 does not directly follow 1.0.0, expected 1.1.0
+latest: not-semver: 'latest' is not a semver compatible version
+`, "2 tag(s) do not follow the commit conventions"},
+		{outputJSON, violations[1:], `[
+  {
+    "tag": "latest",
+    "type": "not-semver",
+    "message": "'latest' is not a semver compatible version"
+  }
+]
+`, "1 tag(s) do not follow the commit conventions"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.output, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			err := writeAuditViolations(out, tc.output, tc.violations)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
@@ -3,15 +3,16 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/arnaud-deprez/gsemver/internal/git"
 	"github.com/arnaud-deprez/gsemver/internal/log"
-	"github.com/arnaud-deprez/gsemver/internal/utils"
 	"github.com/arnaud-deprez/gsemver/pkg/version"
 )
 
@@ -38,6 +39,10 @@ Automatic way assumes:
 Base on this information, it is able to compute the next version.
 
 The manual way is less restrictive and just assumes your previous tags are semver compatible.
+
+With --write, the version is also written in the manifest files of the configuration, such as package.json, Chart.yaml,
+pom.xml, Cargo.toml, a VERSION file or a go file declaring a Version constant. Only the version is replaced, the rest of
+each file is left untouched. With --verify, the command fails if the version of a manifest file is not the version.
 `
 	bumpExample = `
 # To bump automatically:
@@ -70,6 +75,22 @@ gsemver bump --build-metadata "{{(.Commits | first).Hash.Short}}"
 
 # To use bump auto with one or many branch strategies
 gsemver bump --branch-strategy='{"branchesPattern":"^miletone-1.1$","preReleaseTemplate":"beta"}' --branch-strategy='{"branchesPattern":"^miletone-2.0$","preReleaseTemplate":"alpha"}'
+
+# To simulate the next version with hypothetical commits on top of HEAD
+gsemver bump --simulate "feat!: drop v1 API" --simulate "fix: typo"
+# Or from a file where messages are separated by a line containing only ---
+gsemver bump --simulate-file commits.txt
+
+# To write the version in the manifest files of the configuration
+gsemver bump --write
+# Or to check that they have the version
+gsemver bump --verify
+
+# To explain the version, eg. when a Release-As footer forces it
+gsemver bump --explain
+
+# To delegate the bump decision to an external command
+gsemver bump --branch-strategy='{"strategy":"EXEC","branchesPattern":".*","command":"./scripts/bump-plugin.sh","commandTimeout":"10s"}'
 `
 	preReleaseTemplateDesc = `Use pre-release template version such as 'alpha' which will give a version like 'X.Y.Z-alpha.N'.
 If pre-release flag is present but does not contain template value, it will give a version like 'X.Y.Z-N' where 'N' is the next pre-release increment for the version 'X.Y.Z'.
@@ -84,6 +105,13 @@ This flag cannot be used with --pre-release* flags and take precedence over them
 The strategy is defined in json and looks like {"branchesPattern":"^milestone-.*$", "preReleaseTemplate":"alpha"} for example.
 This will use pre-release alpha version for every milestone-* branches. 
 You can find all available options https://godoc.org/github.com/arnaud-deprez/gsemver/pkg/version#BumpBranchesStrategy`
+
+	simulateDesc = `Simulate a commit with this message on top of HEAD to compute the version it would produce.
+It can be repeated and the repository is never modified.`
+
+	simulateFileDesc = `Read simulated commit messages from a file (or - for stdin). Messages are separated by a line containing only ---.`
+
+	simulateSeparator = "---"
 )
 
 // newBumpCommands create the bump command with its subcommands
@@ -118,6 +146,10 @@ func newBumpCommandsWithRun(globalOpts *globalOptions, run func(o *bumpOptions)
 
 			options.PreRelease = cmd.Flags().Changed("pre-release")
 
+			if err := options.readSimulateFile(); err != nil {
+				return err
+			}
+
 			return run(options)
 		},
 	}
@@ -127,37 +159,6 @@ func newBumpCommandsWithRun(globalOpts *globalOptions, run func(o *bumpOptions)
 	return cmd
 }
 
-type config struct {
-	MajorPattern   string
-	MinorPattern   string
-	BumpStrategies []struct {
-		Strategy              string
-		BranchesPattern       string
-		PreRelease            bool
-		PreReleaseTemplate    string
-		PreReleaseOverwrite   bool
-		BuildMetadataTemplate string
-	}
-}
-
-func (c *config) createBumpStrategy() *version.BumpStrategy {
-	ret := version.BumpStrategy{BumpStrategies: []version.BumpBranchesStrategy{}}
-	ret.MajorPattern = regexp.MustCompile(c.MajorPattern)
-	ret.MinorPattern = regexp.MustCompile(c.MinorPattern)
-	for _, it := range c.BumpStrategies {
-		s := version.BumpBranchesStrategy{
-			Strategy:              version.ParseBumpStrategyType(it.Strategy),
-			BranchesPattern:       regexp.MustCompile(it.BranchesPattern),
-			PreRelease:            it.PreRelease,
-			PreReleaseTemplate:    utils.NewTemplate(it.PreReleaseTemplate),
-			PreReleaseOverwrite:   it.PreReleaseOverwrite,
-			BuildMetadataTemplate: utils.NewTemplate(it.BuildMetadataTemplate),
-		}
-		ret.BumpStrategies = append(ret.BumpStrategies, s)
-	}
-	return &ret
-}
-
 // BumpOptions type to represent the available options for the bump commands
 // It extends GlobalOptions.
 type bumpOptions struct {
@@ -176,6 +177,16 @@ type bumpOptions struct {
 	BuildMetadataTemplate string
 	// BranchStrategies is mapped to pkg/version/BumpStrategyOptions#BranchStrategies
 	BranchStrategies []string
+	// Simulate contains the messages of the commits to simulate on top of HEAD
+	Simulate []string
+	// SimulateFile is a file containing messages of commits to simulate on top of HEAD
+	SimulateFile string
+	// Write writes the version in the manifest files of the configuration
+	Write bool
+	// Verify checks that the manifest files of the configuration have the version
+	Verify bool
+	// Explain prints how the version is computed before the version
+	Explain bool
 }
 
 func (o *bumpOptions) addBumpFlags(cmd *cobra.Command) {
@@ -185,23 +196,15 @@ func (o *bumpOptions) addBumpFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.PreReleaseOverwrite, "pre-release-overwrite", false, "Use pre-release overwrite option to remove the pre-release identifier suffix which will give a version like `X.Y.Z-SNAPSHOT` if pre-release=SNAPSHOT")
 	cmd.Flags().StringVar(&o.BuildMetadataTemplate, "build-metadata", "", buildMetadataTemplateDesc)
 	cmd.Flags().StringArrayVar(&o.BranchStrategies, "branch-strategy", []string{}, branchStrategyDesc)
+	cmd.Flags().StringArrayVar(&o.Simulate, "simulate", []string{}, simulateDesc)
+	cmd.Flags().StringVar(&o.SimulateFile, "simulate-file", "", simulateFileDesc)
+	cmd.Flags().BoolVar(&o.Write, "write", false, "Write the version in the manifest files of the configuration")
+	cmd.Flags().BoolVar(&o.Verify, "verify", false, "Fail if the version of a manifest file of the configuration is not the version")
+	cmd.Flags().BoolVar(&o.Explain, "explain", false, "Print how the version is computed before the version, such as the Release-As footer forcing it")
 
 	viper.BindPFlag("majorPattern", cmd.Flags().Lookup("major-pattern"))
 	viper.BindPFlag("minorPattern", cmd.Flags().Lookup("minor-pattern"))
-
-	viper.SetDefault("majorPattern", version.DefaultMajorPattern)
-	viper.SetDefault("minorPattern", version.DefaultMinorPattern)
-	viper.SetDefault("bumpStrategies", []interface{}{
-		map[string]interface{}{
-			"strategy":        "AUTO",
-			"branchesPattern": version.DefaultReleaseBranchesPattern,
-		},
-		map[string]interface{}{
-			"strategy":              "AUTO",
-			"branchesPattern":       ".*",
-			"buildMetadataTemplate": version.DefaultBuildMetadataTemplate,
-		},
-	})
+	setConfigDefaults()
 
 	o.Cmd = cmd
 }
@@ -210,10 +213,18 @@ func (o *bumpOptions) hasDefaultCommandSettings() bool {
 	return strings.ToLower(o.Bump) != "auto" || o.Cmd.Flags().Changed("pre-release") || o.Cmd.Flags().Changed("pre-release-overwrite") || o.Cmd.Flags().Changed("build-metadata")
 }
 
-func (o *bumpOptions) createBumpStrategy() *version.BumpStrategy {
-	viper.Unmarshal(&o.viperConfig)
-	ret := o.viperConfig.createBumpStrategy()
-	ret.SetGitRepository(git.NewVersionGitRepo(o.CurrentDir))
+func (o *bumpOptions) createBumpStrategy() (*version.BumpStrategy, error) {
+	ret, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Simulate) > 0 {
+		gitRepo, err := o.newGitRepo(&o.viperConfig)
+		if err != nil {
+			return nil, err
+		}
+		ret.SetGitRepository(git.NewOverlayVersionGitRepo(gitRepo, o.Simulate...))
+	}
 
 	for id, s := range o.BranchStrategies {
 		if id == 0 {
@@ -231,16 +242,103 @@ func (o *bumpOptions) createBumpStrategy() *version.BumpStrategy {
 		ret.BumpStrategies = []version.BumpBranchesStrategy{defaultStrategy}
 	}
 
-	return ret
+	return ret, nil
+}
+
+// readSimulateFile appends the commit messages from SimulateFile to Simulate
+func (o *bumpOptions) readSimulateFile() error {
+	if o.SimulateFile == "" {
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if o.SimulateFile == "-" {
+		data, err = io.ReadAll(o.ioStreams.In)
+	} else {
+		data, err = os.ReadFile(o.SimulateFile)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "cannot read simulated commits from %s", o.SimulateFile)
+	}
+
+	o.Simulate = append(o.Simulate, splitSimulatedMessages(string(data))...)
+	return nil
+}
+
+func splitSimulatedMessages(content string) []string {
+	var messages []string
+	var sb strings.Builder
+	flush := func() {
+		if message := strings.TrimSpace(sb.String()); message != "" {
+			messages = append(messages, message)
+		}
+		sb.Reset()
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == simulateSeparator {
+			flush()
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	flush()
+	return messages
 }
 
 func run(o *bumpOptions) error {
 	log.Debug("Run bump command with configuration: %#v", o)
 
-	version, err := o.createBumpStrategy().Bump()
+	strategy, err := o.createBumpStrategy()
 	if err != nil {
 		return err
 	}
+	version, context, err := strategy.BumpWithContext()
+	if err != nil {
+		return err
+	}
+	if o.Write || o.Verify {
+		if err := o.syncManifests(version.String()); err != nil {
+			return err
+		}
+	}
+	if o.Explain {
+		writeExplanation(o.ioStreams.Out, context)
+	}
 	fmt.Fprintf(o.ioStreams.Out, "%v", version)
 	return nil
 }
+
+// writeExplanation writes how the version is computed from the context
+func writeExplanation(out io.Writer, context *version.Context) {
+	if context == nil {
+		return
+	}
+	if context.LastTag != nil && context.LastTag.Name != "" {
+		fmt.Fprintf(out, "Last version: %v (tag %s)\n", context.LastVersion, context.LastTag.Name)
+	} else {
+		fmt.Fprintf(out, "Last version: %v (no tag)\n", context.LastVersion)
+	}
+	fmt.Fprintf(out, "Branch: %s\n", context.Branch)
+	fmt.Fprintf(out, "Commits: %d\n", len(context.Commits))
+	if context.ReleaseAs != nil {
+		fmt.Fprintf(out, "%s: %v forced by commit %s %s\n", version.ReleaseAsToken, context.ReleaseAs.Version, context.ReleaseAs.Commit.Hash.Short(), subject(context.ReleaseAs.Commit.Message))
+	}
+}
+
+// syncManifests writes the version in the manifest files or verifies them
+func (o *bumpOptions) syncManifests(version string) error {
+	if o.Write && o.Verify {
+		return errors.New("--write cannot be used with --verify")
+	}
+	files, err := o.manifestFiles(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+	if o.Verify {
+		return verifyManifests(files, version)
+	}
+	_, err = writeManifests(files, version)
+	return err
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	shellquote "github.com/kballard/go-shellquote"
@@ -11,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/arnaud-deprez/gsemver/internal/command"
 	"github.com/arnaud-deprez/gsemver/internal/utils"
 	"github.com/arnaud-deprez/gsemver/pkg/version"
 )
@@ -45,7 +48,8 @@ func TestBumpNoFlag(t *testing.T) {
 			args, err := shellquote.Split(tc.args)
 			assert.NoError(err)
 			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-				s := o.createBumpStrategy()
+				s, err := o.createBumpStrategy()
+				assert.NoError(err)
 
 				assert.Equal(version.DefaultMajorPattern, utils.RegexpToString(s.MajorPattern))
 				assert.Equal(version.DefaultMinorPattern, utils.RegexpToString(s.MinorPattern))
@@ -87,7 +91,8 @@ func TestBumpChangePattern(t *testing.T) {
 			args, err := shellquote.Split(tc.args)
 			assert.NoError(err)
 			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-				s := o.createBumpStrategy()
+				s, err := o.createBumpStrategy()
+				assert.NoError(err)
 
 				assert.Equal(tc.expectedMajorPattern, utils.RegexpToString(s.MajorPattern))
 				assert.Equal(tc.expectedMinorPattern, utils.RegexpToString(s.MinorPattern))
@@ -101,6 +106,49 @@ func TestBumpChangePattern(t *testing.T) {
 	}
 }
 
+func TestBumpConvention(t *testing.T) {
+	testData := []struct {
+		args                 string
+		expectedConvention   string
+		expectedMinorPattern string
+		err                  string
+	}{
+		{`--convention gitmoji`, version.Gitmoji, `^(?::sparkles:|\x{2728})`, ""},
+		{`--convention eslint --minor-pattern 'bar'`, version.ESLint, "bar", ""},
+		{`--convention foo`, "", "", `Unknown convention "foo", expected one of angular, atom, conventional, eslint, gitmoji, jquery`},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.args, func(t *testing.T) {
+			assert := assert.New(t)
+			globalOpts := &globalOptions{
+				ioStreams: newIOStreams(os.Stdin, new(bytes.Buffer), new(bytes.Buffer)),
+			}
+
+			args, err := shellquote.Split(tc.args)
+			assert.NoError(err)
+			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
+				s, err := o.createBumpStrategy()
+				if err != nil {
+					return err
+				}
+
+				assert.Equal(tc.expectedConvention, s.Convention)
+				assert.Equal(tc.expectedMinorPattern, utils.RegexpToString(s.MinorPattern))
+				return nil
+			})
+			globalOpts.addGlobalFlags(root)
+
+			_, err = executeCommand(root, args...)
+			if tc.err == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, tc.err)
+			}
+		})
+	}
+}
+
 func TestBumpPreRelease(t *testing.T) {
 	testData := []struct {
 		args                        string
@@ -127,7 +175,8 @@ func TestBumpPreRelease(t *testing.T) {
 			args, err := shellquote.Split(tc.args)
 			assert.NoError(err)
 			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-				s := o.createBumpStrategy()
+				s, err := o.createBumpStrategy()
+				assert.NoError(err)
 
 				assert.Len(s.BumpStrategies, 1)
 				assert.Equal(".*", utils.RegexpToString(s.BumpStrategies[0].BranchesPattern))
@@ -174,7 +223,8 @@ func TestBumpBuildMetadata(t *testing.T) {
 			args, err := shellquote.Split(tc.args)
 			assert.NoError(err)
 			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-				s := o.createBumpStrategy()
+				s, err := o.createBumpStrategy()
+				assert.NoError(err)
 
 				assert.Equal(len(tc.expectedBumpBranchesStrategy), len(s.BumpStrategies))
 				for i := range tc.expectedBumpBranchesStrategy {
@@ -215,7 +265,8 @@ func TestBumpBranchStrategy(t *testing.T) {
 			args, err := shellquote.Split(tc.args)
 			assert.NoError(err)
 			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-				s := o.createBumpStrategy()
+				s, err := o.createBumpStrategy()
+				assert.NoError(err)
 
 				size := 1
 				if tc.args == "" {
@@ -249,19 +300,39 @@ func TestWithConfiguration(t *testing.T) {
 	//args, err := shellquote.Split(tc.args)
 	// assert.NoError(err)
 	cmd := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
-		s := o.createBumpStrategy()
+		s, err := o.createBumpStrategy()
+		assert.NoError(err)
 
 		assert.Equal("majorPatternConfig", s.MajorPattern.String(), "majorPattern does not match")
 		assert.Equal("minorPatternConfig", s.MinorPattern.String(), "minorPattern does not match")
+		assert.Equal(version.ScopeRules{
+			Ignore: []string{"deps"},
+			Minor:  version.ScopeFilter{Deny: []string{"internal"}},
+		}, s.Scopes, "scopes does not match")
+		assert.Len(s.IssuePatterns, 1)
+		assert.Equal(`JIRA-\d+`, s.IssuePatterns[0].Pattern.String())
+		assert.Equal("https://jira.example.com/browse/{{.ID}}", utils.TemplateToString(s.IssuePatterns[0].URLTemplate))
+		assert.Equal("https://github.com/owner/repo", s.RepositoryURL)
+		isPullRequest := true
 		expectedBumpBranchesStrategy := []version.BumpBranchesStrategy{
+			{
+				Strategy:              version.AUTO,
+				BranchesPattern:       regexp.MustCompile(".*"),
+				PullRequest:           &isPullRequest,
+				TargetBranchesPattern: regexp.MustCompile("targetBranchesPattern"),
+				PreRelease:            true,
+				PreReleaseTemplate:    utils.NewTemplate("pr.{{.CI.PullRequest.Number}}"),
+			},
 			{
 				Strategy:        version.AUTO,
 				BranchesPattern: regexp.MustCompile("releaseBranchesPattern"),
+				DirtyPolicy:     version.DirtyFail,
 			},
 			{
 				Strategy:              version.AUTO,
 				BranchesPattern:       regexp.MustCompile("all"),
 				BuildMetadataTemplate: utils.NewTemplate("myBuildMetadataTemplate"),
+				DirtyPolicy:           version.DirtyAppend,
 			},
 		}
 		assert.Equal(len(expectedBumpBranchesStrategy), len(s.BumpStrategies))
@@ -279,12 +350,28 @@ func TestWithConfiguration(t *testing.T) {
 		var yamlConfig = []byte(`
 majorPattern: "majorPatternConfig"
 minorPattern: "minorPatternConfig"
+scopes:
+  ignore: ["deps"]
+  minor:
+    deny: ["internal"]
+issuePatterns:
+- pattern: "JIRA-\\d+"
+  url: "https://jira.example.com/browse/{{.ID}}"
+repositoryUrl: "https://github.com/owner/repo"
 bumpStrategies:
+- branchesPattern: ".*"
+  pullRequest: true
+  targetBranchesPattern: "targetBranchesPattern"
+  strategy: "AUTO"
+  preRelease: true
+  preReleaseTemplate: "pr.{{.CI.PullRequest.Number}}"
 - branchesPattern: "releaseBranchesPattern"
   strategy: "AUTO"
+  dirtyPolicy: "fail"
 - branchesPattern: "all"
   strategy: "AUTO"
   buildMetadataTemplate: "myBuildMetadataTemplate"
+  dirtyPolicy: "append"
 `)
 		err := viper.ReadConfig(bytes.NewBuffer(yamlConfig))
 		assert.NoError(err, "Cannot read configuration")
@@ -293,3 +380,290 @@ bumpStrategies:
 	_, err := executeCommand(cmd)
 	assert.NoError(err)
 }
+
+func TestConfigInvalidPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, key := range []string{"majorPattern", "minorPattern", "patchPattern", "commitPattern", "releaseCommitPattern", "branchesPattern", "targetBranchesPattern"} {
+		t.Run(key, func(_ *testing.T) {
+			yamlConfig := "convention: conventional\n" + key + `: "("`
+			switch key {
+			case "branchesPattern":
+				yamlConfig = "convention: conventional\n" + `bumpStrategies: [{branchesPattern: "("}]`
+			case "targetBranchesPattern":
+				yamlConfig = "convention: conventional\n" + `bumpStrategies: [{branchesPattern: ".*", targetBranchesPattern: "("}]`
+			}
+			v := viper.New()
+			v.SetConfigType("yaml")
+			assert.NoError(v.ReadConfig(bytes.NewBufferString(yamlConfig)))
+			c := &config{}
+			assert.NoError(v.Unmarshal(c))
+
+			_, err := c.createBumpStrategy()
+			assert.EqualError(err, "invalid "+key+" \"(\": error parsing regexp: missing closing ): `(`")
+		})
+	}
+}
+
+func TestConfigInvalidIssuePattern(t *testing.T) {
+	c := &config{Convention: version.DefaultConvention}
+	c.IssuePatterns = append(c.IssuePatterns, struct {
+		Pattern string
+		URL     string
+	}{Pattern: `JIRA-(\d+`})
+	_, err := c.createBumpStrategy()
+	assert.EqualError(t, err, "invalid issue pattern \"JIRA-(\\\\d+\": error parsing regexp: missing closing ): `JIRA-(\\d+`")
+}
+
+func TestConfigReleaseCommitPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	// the release commits are kept unless they are created
+	c := &config{Convention: version.DefaultConvention}
+	c.Release.CommitMessageTemplate = version.DefaultReleaseCommitTemplate
+	s, err := c.createBumpStrategy()
+	assert.NoError(err)
+	assert.Nil(s.ReleaseCommitPattern)
+
+	c.Release.Commit = true
+	s, err = c.createBumpStrategy()
+	assert.NoError(err)
+	assert.Regexp(s.ReleaseCommitPattern, "chore(release): v1.2.0")
+	assert.NotRegexp(s.ReleaseCommitPattern, "chore(release): prepare 1.2.0")
+
+	c.ReleaseCommitPattern = `^chore\(release\): `
+	s, err = c.createBumpStrategy()
+	assert.NoError(err)
+	assert.Regexp(s.ReleaseCommitPattern, "chore(release): prepare 1.2.0")
+
+	c.ReleaseCommitPattern = ""
+	c.Release.CommitMessageTemplate = "chore: release"
+	_, err = c.createBumpStrategy()
+	assert.EqualError(err, "Release commit message template 'chore: release' does not render the version")
+}
+
+func TestBumpSimulate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "commits.txt")
+	assert.NoError(t, os.WriteFile(file, []byte("feat: from file\n\nwith a body\n---\nfix: another one\n---\n"), 0644))
+
+	testData := []struct {
+		args     string
+		stdin    string
+		expected []string
+	}{
+		{``, "", []string{}},
+		{`--simulate 'feat!: drop v1 API'`, "", []string{"feat!: drop v1 API"}},
+		{`--simulate 'feat: a' --simulate 'fix: b'`, "", []string{"feat: a", "fix: b"}},
+		{`--simulate 'feat: a' --simulate-file ` + file, "", []string{"feat: a", "feat: from file\n\nwith a body", "fix: another one"}},
+		{`--simulate-file -`, "fix: from stdin\r\n---\r\nfeat: again", []string{"fix: from stdin", "feat: again"}},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.args, func(t *testing.T) {
+			assert := assert.New(t)
+			out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+			globalOpts := &globalOptions{
+				ioStreams: newIOStreams(strings.NewReader(tc.stdin), out, errOut),
+			}
+
+			args, err := shellquote.Split(tc.args)
+			assert.NoError(err)
+			root := newBumpCommandsWithRun(globalOpts, func(o *bumpOptions) error {
+				assert.Equal(tc.expected, o.Simulate)
+				return nil
+			})
+			globalOpts.addGlobalFlags(root)
+
+			_, err = executeCommand(root, args...)
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestBumpSimulateFileNotFound(t *testing.T) {
+	globalOpts := &globalOptions{
+		ioStreams: newIOStreams(os.Stdin, new(bytes.Buffer), new(bytes.Buffer)),
+	}
+	root := newBumpCommandsWithRun(globalOpts, func(_ *bumpOptions) error { return nil })
+	globalOpts.addGlobalFlags(root)
+
+	_, err := executeCommand(root, "--simulate-file", "does-not-exist.txt")
+	assert.ErrorContains(t, err, "cannot read simulated commits from does-not-exist.txt")
+}
+
+func TestBumpWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "v0.1.0")
+	git("commit", "--allow-empty", "-m", "fix: handle empty body")
+
+	packageJSON, versionFile := filepath.Join(dir, "package.json"), filepath.Join(dir, "VERSION")
+	assert.NoError(os.WriteFile(packageJSON, []byte("{\n  \"name\": \"app\",\n  \"version\": \"0.1.0\"\n}\n"), 0644))
+	assert.NoError(os.WriteFile(versionFile, []byte("0.1.0\n"), 0644))
+	viper.Set("manifests", []map[string]interface{}{{"path": "package.json"}, {"path": "VERSION", "format": "text"}})
+	t.Cleanup(func() { viper.Set("manifests", nil) })
+
+	bump := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newBumpCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	// the version depends on the bump strategies of the configuration
+	_, err := bump("--verify")
+	assert.ErrorContains(err, "manifest files out of sync with version 0.1.")
+	assert.ErrorContains(err, packageJSON+" (0.1.0), "+versionFile+" (0.1.0)")
+
+	v, err := bump("--write")
+	assert.NoError(err)
+	assert.Regexp(`^0\.1\.`, v)
+	content, err := os.ReadFile(packageJSON)
+	assert.NoError(err)
+	assert.Equal("{\n  \"name\": \"app\",\n  \"version\": \""+v+"\"\n}\n", string(content))
+	content, err = os.ReadFile(versionFile)
+	assert.NoError(err)
+	assert.Equal(v+"\n", string(content))
+
+	out, err := bump("--verify")
+	assert.NoError(err)
+	assert.Equal(v, out)
+
+	_, err = bump("--write", "--verify")
+	assert.EqualError(err, "--write cannot be used with --verify")
+}
+
+func TestBumpGitBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	git("commit", "--allow-empty", "-m", "feat: second feature")
+	git("gc", "--prune=now")
+
+	bump := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newBumpCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	// the version depends on the bump strategies of the configuration
+	expected, err := bump("--git-backend", "cli")
+	assert.NoError(err)
+	assert.Regexp(`^0\.`, expected)
+	out, err := bump("--git-backend", "go")
+	assert.NoError(err)
+	assert.Equal(expected, out)
+
+	_, err = bump("--git-backend", "jgit")
+	assert.EqualError(err, `unknown git backend "jgit", expected one of cli, go`)
+}
+
+func TestBumpFetch(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	git("commit", "--allow-empty", "-m", "feat: second feature")
+	// the remote cannot be reached like in an air-gapped sandbox
+	git("remote", "add", "origin", filepath.Join(dir, "unreachable.git"))
+
+	bump := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newBumpCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	_, err := bump()
+	assert.ErrorContains(err, "Cannot fetch tags caused by: ")
+	_, err = bump("--fetch-remote", "upstream", "--fetch-refspec", "main")
+	assert.ErrorContains(err, "Cannot fetch tags caused by: ")
+
+	// the version is computed with the local tags
+	out, err := bump("--no-fetch")
+	assert.NoError(err)
+	assert.Regexp(`^0\.`, out)
+	expected := out
+	out, err = bump("--ignore-fetch-errors")
+	assert.NoError(err)
+	assert.Equal(expected, out)
+}
+
+func TestBumpExplain(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	git("init", "--bare", filepath.Join(dir, ".git", "remote.git"))
+	git("remote", "add", "origin", filepath.Join(dir, ".git", "remote.git"))
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	git("commit", "--allow-empty", "-m", "chore: release 2.0.0\n\nRelease-As: 2.0.0")
+
+	bump := func(args ...string) string {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newBumpCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		assert.NoError(err)
+		return out.String()
+	}
+
+	// the explanation is only printed on demand, so the output of a script is the version
+	expected := bump()
+	assert.Regexp(`^\d+\.\d+\.\d+`, expected)
+	out := bump("--explain")
+	assert.Contains(out, "Last version: 0.1.0 (tag v0.1.0)\n")
+	assert.Contains(out, "Branch: release/all\n")
+	assert.Contains(out, "Commits: 1\n")
+	assert.Regexp(`(?m)^Release-As: 2\.0\.0 forced by commit [0-9a-f]{7} chore: release 2\.0\.0$`, out)
+	assert.True(strings.HasSuffix(out, "\n"+expected), "the version is printed after the explanation")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	changelogDesc = `
+This will render the release notes of the commits between 2 revisions in Markdown, using the same commits and conventions
+as the bump command.
+
+The changes are grouped by commit type (Features, Bug Fixes, ...) and the breaking changes are called out in their own
+section. Reverted commits and merge commits are left out.
+
+By default, it renders the release notes of the commits since the last tag with the version 'gsemver bump' would compute.
+If --to is a version tag, the release notes of this version are rendered instead.
+With --all, the release notes of every version tag are rendered, from the most recent to the oldest.
+
+With --write, the release notes are inserted in a changelog file above the release notes of the previous version.
+The release notes of a version already in the file are replaced, so it can be run again for the same version, and the
+rest of the file is preserved. The release notes of each version must start with a '## ' heading followed by the version.
+
+The layout can be the default one or keep-a-changelog (https://keepachangelog.com) in which features are Added, breaking
+changes, performance improvements and refactorings are Changed, reverts are Removed and bug fixes are Fixed.
+
+The release notes can be customized with a go template using sprig functions (http://masterminds.github.io/sprig/).
+The template is applied to each release which exposes Version, Tag, PreviousTag, Date, RepositoryURL, BreakingChanges,
+Sections and Issues. Each section has a Type, a Title and Entries, each entry exposes the parsed commit (Type, Scope,
+Description, Body, BreakingChange, Footers, ...), its Commit and its Issues.
+
+The issues are the references matching the configured issuePatterns, #123 and GH-123 by default. Each issue has a Ref,
+an ID and a URL computed from the url template of its pattern or else from the repositoryUrl configuration or the
+remote the tags are fetched from.
+`
+	changelogExample = `
+# To render the release notes of the next version
+gsemver changelog
+
+# To render the release notes of a version
+gsemver changelog --to v1.2.0
+
+# To render the release notes of every version
+gsemver changelog --all
+
+# To add the release notes of the next version to CHANGELOG.md
+gsemver changelog --write CHANGELOG.md
+
+# To regenerate every version of a Keep a Changelog file
+gsemver changelog --all --layout keep-a-changelog --write CHANGELOG.md
+
+# To render the release notes with a custom template
+gsemver changelog --template .github/release-notes.tmpl
+`
+
+	changelogLayoutDefault        = "default"
+	changelogLayoutKeepAChangelog = "keep-a-changelog"
+)
+
+// changelogLayouts are the header of a new changelog file and the template of the release notes of each layout
+var changelogLayouts = map[string]struct{ header, template string }{
+	changelogLayoutDefault:        {version.DefaultChangelogHeader, version.DefaultChangelogTemplate},
+	changelogLayoutKeepAChangelog: {version.KeepAChangelogHeader, version.KeepAChangelogTemplate},
+}
+
+// newChangelogCommands create the changelog command
+func newChangelogCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &changelogOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "changelog",
+		Short:        "Render the release notes from the commits",
+		Long:         changelogDesc,
+		Example:      changelogExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addChangelogFlags(cmd)
+
+	return cmd
+}
+
+// changelogOptions type to represent the available options for the changelog command
+// It extends GlobalOptions.
+type changelogOptions struct {
+	*globalOptions
+	viperConfig config
+	// From is the revision (excluded) from where to render the release notes
+	From string
+	// To is the revision up to which to render the release notes
+	To string
+	// All renders the release notes of every version
+	All bool
+	// Template is the file of the go template of the release notes
+	Template string
+	// Layout is the layout of the release notes: default or keep-a-changelog
+	Layout string
+	// Write is the changelog file to update instead of printing the release notes
+	Write string
+}
+
+func (o *changelogOptions) addChangelogFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.From, "from", "", "Render the commits after this revision (excluded). By default, it starts from the previous tag")
+	cmd.Flags().StringVar(&o.To, "to", "HEAD", "Render the commits up to this revision")
+	cmd.Flags().BoolVar(&o.All, "all", false, "Render the release notes of every version")
+	cmd.Flags().StringVar(&o.Template, "template", "", "Use the go template of this file to render the release notes")
+	cmd.Flags().StringVar(&o.Layout, "layout", changelogLayoutDefault, "Layout of the release notes: default or keep-a-changelog")
+	cmd.Flags().StringVar(&o.Write, "write", "", "Insert the release notes in this changelog file instead of printing them")
+
+	o.Cmd = cmd
+}
+
+func (o *changelogOptions) run() error {
+	log.Debug("Run changelog command with configuration: %#v", o)
+
+	if o.All && (o.Cmd.Flags().Changed("from") || o.Cmd.Flags().Changed("to")) {
+		return errors.New("--all cannot be used with --from or --to")
+	}
+
+	layout, ok := changelogLayouts[o.Layout]
+	if !ok {
+		return errors.Errorf("unknown layout %q. Try '%s' or '%s'", o.Layout, changelogLayoutDefault, changelogLayoutKeepAChangelog)
+	}
+	tmpl, err := o.changelogTemplate(layout.template)
+	if err != nil {
+		return err
+	}
+
+	strategy, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+
+	var releases []version.Release
+	if o.All {
+		releases, err = strategy.Releases()
+	} else {
+		var r *version.Release
+		if r, err = strategy.Release(o.From, o.To); r != nil {
+			releases = []version.Release{*r}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.Write != "" {
+		return o.writeChangelog(layout.header, tmpl, releases)
+	}
+	return version.RenderChangelog(o.ioStreams.Out, tmpl, releases)
+}
+
+// writeChangelog inserts the release notes in the Write file, the file is created with the header if it does not exist
+func (o *changelogOptions) writeChangelog(header string, tmpl *template.Template, releases []version.Release) error {
+	content, err := os.ReadFile(o.Write)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "cannot read %s", o.Write)
+	}
+	updated, err := version.UpdateChangelog(string(content), header, tmpl, releases)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(o.Write, []byte(updated), 0644); err != nil {
+		return errors.Wrapf(err, "cannot write %s", o.Write)
+	}
+
+	versions := make([]string, len(releases))
+	for i, r := range releases {
+		versions[i] = r.Version.String()
+	}
+	fmt.Fprintf(o.ioStreams.Out, "%s updated with %s\n", o.Write, strings.Join(versions, ", "))
+	return nil
+}
+
+// changelogTemplate returns the template of the release notes, the one of the layout unless Template is defined
+func (o *changelogOptions) changelogTemplate(text string) (tmpl *template.Template, err error) {
+	if o.Template != "" {
+		data, err := os.ReadFile(o.Template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read template %s", o.Template)
+		}
+		text = string(data)
+	}
+
+	// utils.NewTemplate panics on an invalid template
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("invalid template %s: %v", o.Template, r)
+		}
+	}()
+	return utils.NewTemplate(text), nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+)
+
+func TestChangelog(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+	}
+	git("init")
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "v0.1.0")
+	git("commit", "--allow-empty", "-m", "fix(parser): handle empty body")
+
+	template := filepath.Join(t.TempDir(), "changelog.tmpl")
+	assert.NoError(os.WriteFile(template, []byte(`{{.Version}}:{{range .Sections}}{{range .Entries}} {{.Type}}={{.Description}}{{end}}{{end}}
+`), 0644))
+
+	changelog := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newChangelogCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	// the version of the unreleased commits depends on the bump strategies of the configuration
+	out, err := changelog("--template", template)
+	assert.NoError(err)
+	assert.Contains(out, ": fix=handle empty body\n")
+
+	out, err = changelog()
+	assert.NoError(err)
+	assert.Contains(out, "### Bug Fixes\n\n* **parser:** handle empty body (")
+
+	git("tag", "v0.1.1")
+
+	out, err = changelog("--template", template, "--to", "v0.1.0")
+	assert.NoError(err)
+	assert.Equal("0.1.0: feat=initial feature\n", out)
+
+	out, err = changelog("--template", template, "--all")
+	assert.NoError(err)
+	assert.Equal("0.1.1: fix=handle empty body\n\n0.1.0: feat=initial feature\n", out)
+
+	file := filepath.Join(dir, "CHANGELOG.md")
+	assert.NoError(os.WriteFile(file, []byte("# Changelog\n\n## [0.1.0]\n\nEdited by hand.\n"), 0644))
+	for i := 0; i < 2; i++ {
+		out, err = changelog("--all", "--layout", "keep-a-changelog", "--write", file)
+		assert.NoError(err)
+		assert.Equal(file+" updated with 0.1.1, 0.1.0\n", out)
+	}
+	content, err := os.ReadFile(file)
+	assert.NoError(err)
+	assert.Regexp(`^# Changelog\n\n## \[0\.1\.1\] - \d{4}-\d{2}-\d{2}\n\n### Fixed\n\n- \*\*parser:\*\* handle empty body\n\n## \[0\.1\.0\] - `, string(content))
+
+	_, err = changelog("--layout", "unknown")
+	assert.EqualError(err, `unknown layout "unknown". Try 'default' or 'keep-a-changelog'`)
+
+	_, err = changelog("--all", "--from", "v0.1.0")
+	assert.EqualError(err, "--all cannot be used with --from or --to")
+
+	assert.NoError(os.WriteFile(template, []byte(`{{.Version`), 0644))
+	_, err = changelog("--template", template)
+	assert.ErrorContains(err, "invalid template "+template)
+}
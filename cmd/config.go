@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/arnaud-deprez/gsemver/internal/git"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+type config struct {
+	Convention        string
+	MajorPattern      string
+	MinorPattern      string
+	PatchPattern      string
+	CommitPattern     string
+	Scopes            version.ScopeRules
+	SplitCommitBodies bool
+	IssuePatterns     []struct {
+		Pattern string
+		URL     string
+	}
+	RepositoryURL        string
+	ReleaseCommitPattern string
+	BumpStrategies       []struct {
+		Strategy              string
+		BranchesPattern       string
+		PullRequest           *bool
+		TargetBranchesPattern string
+		PreRelease            bool
+		PreReleaseTemplate    string
+		PreReleaseOverwrite   bool
+		BuildMetadataTemplate string
+		Command               string
+		CommandTimeout        time.Duration
+		DirtyPolicy           string
+	}
+	Tag struct {
+		NameTemplate    string
+		MessageTemplate string
+		Sign            bool
+	}
+	Manifests []struct {
+		Path   string
+		Format string
+	}
+	Release struct {
+		Commit                bool
+		CommitMessageTemplate string
+	}
+	GitBackend string
+	Fetch      struct {
+		Skip         bool
+		Remote       string
+		Refspec      string
+		IgnoreErrors bool
+	}
+}
+
+func (c *config) createBumpStrategy() (*version.BumpStrategy, error) {
+	ret, err := version.NewBumpStrategyWithConvention(c.Convention, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the patterns of the configuration override the ones of the convention
+	if c.MajorPattern != "" {
+		if ret.MajorPattern, err = compileConfigPattern("majorPattern", c.MajorPattern); err != nil {
+			return nil, err
+		}
+	}
+	if c.MinorPattern != "" {
+		if ret.MinorPattern, err = compileConfigPattern("minorPattern", c.MinorPattern); err != nil {
+			return nil, err
+		}
+	}
+	if c.PatchPattern != "" {
+		if ret.PatchPattern, err = compileConfigPattern("patchPattern", c.PatchPattern); err != nil {
+			return nil, err
+		}
+	}
+	if c.CommitPattern != "" {
+		if ret.CommitPattern, err = compileConfigPattern("commitPattern", c.CommitPattern); err != nil {
+			return nil, err
+		}
+	}
+	ret.Scopes = c.Scopes
+	ret.SplitCommitBodies = c.SplitCommitBodies
+	// the issue patterns of the configuration replace the default one
+	if c.IssuePatterns != nil {
+		ret.IssuePatterns = []version.IssuePattern{}
+		for _, it := range c.IssuePatterns {
+			pattern, err := compileConfigPattern("issue pattern", it.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			ret.IssuePatterns = append(ret.IssuePatterns, version.IssuePattern{
+				Pattern:     pattern,
+				URLTemplate: utils.NewTemplate(it.URL),
+			})
+		}
+	}
+	ret.RepositoryURL = c.RepositoryURL
+	// the release commits are ignored only if they are created, with the pattern of their message template by default
+	if c.ReleaseCommitPattern != "" {
+		if ret.ReleaseCommitPattern, err = compileConfigPattern("releaseCommitPattern", c.ReleaseCommitPattern); err != nil {
+			return nil, err
+		}
+	} else if c.Release.Commit {
+		messageTemplate, err := parseTemplate("release commit message", c.Release.CommitMessageTemplate)
+		if err != nil {
+			return nil, err
+		}
+		if ret.ReleaseCommitPattern, err = version.NewReleaseCommitPattern(messageTemplate); err != nil {
+			return nil, err
+		}
+	}
+	ret.BumpStrategies = []version.BumpBranchesStrategy{}
+	for _, it := range c.BumpStrategies {
+		branchesPattern, err := compileConfigPattern("branchesPattern", it.BranchesPattern)
+		if err != nil {
+			return nil, err
+		}
+		dirtyPolicy, err := version.ParseDirtyPolicy(it.DirtyPolicy)
+		if err != nil {
+			return nil, err
+		}
+		s := version.BumpBranchesStrategy{
+			Strategy:              version.ParseBumpStrategyType(it.Strategy),
+			BranchesPattern:       branchesPattern,
+			PullRequest:           it.PullRequest,
+			PreRelease:            it.PreRelease,
+			PreReleaseTemplate:    utils.NewTemplate(it.PreReleaseTemplate),
+			PreReleaseOverwrite:   it.PreReleaseOverwrite,
+			BuildMetadataTemplate: utils.NewTemplate(it.BuildMetadataTemplate),
+			Command:               it.Command,
+			CommandTimeout:        it.CommandTimeout,
+			DirtyPolicy:           dirtyPolicy,
+		}
+		if it.TargetBranchesPattern != "" {
+			if s.TargetBranchesPattern, err = compileConfigPattern("targetBranchesPattern", it.TargetBranchesPattern); err != nil {
+				return nil, err
+			}
+		}
+		ret.BumpStrategies = append(ret.BumpStrategies, s)
+	}
+	ret.Fetch = c.fetchOptions()
+	return ret, nil
+}
+
+// compileConfigPattern compiles the pattern of a configuration key
+func compileConfigPattern(key string, pattern string) (*regexp.Regexp, error) {
+	ret, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s %q", key, pattern)
+	}
+	return ret, nil
+}
+
+// fetchOptions returns the options of the tag fetch
+func (c *config) fetchOptions() version.FetchOptions {
+	return version.FetchOptions{
+		Skip:         c.Fetch.Skip,
+		Remote:       c.Fetch.Remote,
+		Refspec:      c.Fetch.Refspec,
+		IgnoreErrors: c.Fetch.IgnoreErrors,
+	}
+}
+
+// setConfigDefaults sets the default configuration which follows Conventional Commits.
+// The patterns default to the ones of the convention.
+func setConfigDefaults() {
+	viper.SetDefault("convention", version.DefaultConvention)
+	viper.SetDefault("tag.nameTemplate", version.DefaultTagNameTemplate)
+	viper.SetDefault("tag.messageTemplate", version.DefaultTagMessageTemplate)
+	viper.SetDefault("release.commitMessageTemplate", version.DefaultReleaseCommitTemplate)
+	viper.SetDefault("gitBackend", git.BackendCLI)
+	viper.SetDefault("bumpStrategies", []interface{}{
+		map[string]interface{}{
+			"strategy":        "AUTO",
+			"branchesPattern": version.DefaultReleaseBranchesPattern,
+		},
+		map[string]interface{}{
+			"strategy":              "AUTO",
+			"branchesPattern":       ".*",
+			"buildMetadataTemplate": version.DefaultBuildMetadataTemplate,
+		},
+	})
+}
+
+// createBumpStrategyFromConfig creates the BumpStrategy from the configuration for the current directory.
+// The unmarshalled configuration is stored in c.
+func (o *globalOptions) createBumpStrategyFromConfig(c *config) (*version.BumpStrategy, error) {
+	setConfigDefaults()
+	viper.Unmarshal(c)
+	ret, err := c.createBumpStrategy()
+	if err != nil {
+		return nil, err
+	}
+	gitRepo, err := o.newGitRepo(c)
+	if err != nil {
+		return nil, err
+	}
+	ret.SetGitRepository(gitRepo)
+	ret.CI = ci.Detect()
+	return ret, nil
+}
+
+// newGitRepo creates the version.GitRepo for the current directory with the git backend and the fetch options of the configuration
+func (o *globalOptions) newGitRepo(c *config) (version.GitRepo, error) {
+	return git.NewVersionGitRepoWithBackend(c.GitBackend, o.CurrentDir, c.fetchOptions())
+}
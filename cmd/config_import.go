@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	importconfig "github.com/arnaud-deprez/gsemver/internal/config"
+	"github.com/arnaud-deprez/gsemver/internal/log"
+)
+
+const (
+	configDesc = `
+This will manage the gsemver configuration.
+`
+	configImportDesc = `
+This will translate the configuration of another release tool into a gsemver configuration written on the standard output.
+
+Supported tools are:
+- semantic-release: the branches become bump strategies (pre-release branches produce pre-releases) and the preset and
+  release rules of the commit analyzer select the convention and extend its patterns.
+- release-please: the visible changelog sections define the commits triggering a release and the prerelease options
+  apply to the release branches.
+
+Anything that cannot be mapped, such as distribution channels or maintenance ranges, is reported as a warning.
+`
+	configImportExample = `
+# To import a semantic-release configuration
+gsemver config import --from semantic-release .releaserc.json > .gsemver.yaml
+
+# To import a release-please configuration
+gsemver config import --from release-please release-please-config.json > .gsemver.yaml
+`
+
+	importFromSemanticRelease = "semantic-release"
+	importFromReleasePlease   = "release-please"
+)
+
+// configImporters are the functions translating the configuration of other release tools
+var configImporters = map[string]func([]byte) (*importconfig.Config, importconfig.Warnings, error){
+	importFromSemanticRelease: importconfig.ImportSemanticRelease,
+	importFromReleasePlease:   importconfig.ImportReleasePlease,
+}
+
+// newConfigCommands create the config command and its sub-commands
+func newConfigCommands(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the gsemver configuration",
+		Long:  configDesc,
+		Run:   runHelp,
+	}
+
+	cmd.AddCommand(newConfigImportCommands(globalOpts))
+
+	return cmd
+}
+
+// newConfigImportCommands create the config import command
+func newConfigImportCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &configImportOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "import <file>",
+		Short:        "Translate the configuration of another release tool",
+		Long:         configImportDesc,
+		Example:      configImportExample,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			options.File = args[0]
+			return options.run()
+		},
+	}
+
+	cmd.Flags().StringVar(&options.From, "from", "", fmt.Sprintf("The release tool of the configuration: %s or %s", importFromSemanticRelease, importFromReleasePlease))
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+// configImportOptions type to represent the available options for the config import command
+// It extends GlobalOptions.
+type configImportOptions struct {
+	*globalOptions
+	// From is the release tool of the configuration
+	From string
+	// File is the configuration file to import
+	File string
+}
+
+func (o *configImportOptions) run() error {
+	log.Debug("Run config import command with configuration: %#v", o)
+
+	importer, ok := configImporters[o.From]
+	if !ok {
+		return errors.Errorf("cannot import from %q, expected %s or %s", o.From, importFromSemanticRelease, importFromReleasePlease)
+	}
+	data, err := os.ReadFile(o.File)
+	if err != nil {
+		return errors.Wrapf(err, "cannot read %s", o.File)
+	}
+	c, warnings, err := importer(data)
+	if err != nil {
+		return errors.Wrapf(err, "cannot import %s", o.File)
+	}
+	for _, w := range warnings {
+		log.Warn("%s", w)
+	}
+	out, err := c.Marshal()
+	if err != nil {
+		return errors.Wrap(err, "cannot encode the configuration")
+	}
+	_, err = o.ioStreams.Out.Write(out)
+	return err
+}
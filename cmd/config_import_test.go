@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigImport(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".releaserc.json")
+	assert.NoError(os.WriteFile(file, []byte(`{"branches": ["main", {"name": "beta", "prerelease": true}]}`), 0644))
+
+	importConfig := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newConfigCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		_, err := executeCommand(cmd, append([]string{"import"}, args...)...)
+		return out.String(), err
+	}
+
+	out, err := importConfig("--from", "semantic-release", file)
+	assert.NoError(err)
+	assert.Equal(`convention: angular
+bumpStrategies:
+  - branchesPattern: ^main$
+    strategy: AUTO
+  - branchesPattern: ^beta$
+    strategy: AUTO
+    preRelease: true
+    preReleaseTemplate: beta
+  - branchesPattern: .*
+    strategy: AUTO
+    buildMetadataTemplate: '{{.Commits | len}}.{{(.Commits | first).Hash.Short}}'
+`, out)
+
+	_, err = importConfig("--from", "goreleaser", file)
+	assert.EqualError(err, `cannot import from "goreleaser", expected semantic-release or release-please`)
+
+	_, err = importConfig("--from", "release-please", filepath.Join(dir, "missing.json"))
+	assert.Error(err)
+
+	_, err = importConfig(file)
+	assert.Error(err)
+}
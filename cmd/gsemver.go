@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -8,13 +9,21 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/arnaud-deprez/gsemver/internal/git"
 	log "github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
 )
 
 const (
-	optionConfig   = "config"
-	optionVerbose  = "verbose"
-	optionLogLevel = "log-level"
+	optionConfig            = "config"
+	optionVerbose           = "verbose"
+	optionLogLevel          = "log-level"
+	optionConvention        = "convention"
+	optionGitBackend        = "git-backend"
+	optionNoFetch           = "no-fetch"
+	optionFetchRemote       = "fetch-remote"
+	optionFetchRefspec      = "fetch-refspec"
+	optionIgnoreFetchErrors = "ignore-fetch-errors"
 )
 
 var (
@@ -83,7 +92,15 @@ func newRootCommand(in io.Reader, out, errout io.Writer) *cobra.Command {
 	globalOpts.addGlobalFlags(cmds)
 
 	cmds.AddCommand(
+		newAuditCommands(globalOpts),
 		newBumpCommands(globalOpts),
+		newChangelogCommands(globalOpts),
+		newConfigCommands(globalOpts),
+		newHistoryCommands(globalOpts),
+		newHooksCommands(globalOpts),
+		newLintCommands(globalOpts),
+		newReleaseCommands(globalOpts),
+		newTagCommands(globalOpts),
 		newVersionCommands(globalOpts),
 		// Hidden documentation generator command: 'helm docs'
 		newDocsCommands(globalOpts),
@@ -120,6 +137,18 @@ func (o *globalOptions) addGlobalFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&o.ConfigFile, optionConfig, "c", "", "config file (default is .gsemver.yaml)")
 	cmd.PersistentFlags().BoolVarP(&o.Verbose, optionVerbose, "v", false, "Enables verbose output by setting log level to debug. This is a shortland to --log-level debug.")
 	cmd.PersistentFlags().StringVarP(&o.LogLevel, optionLogLevel, "", "info", "Sets the logging level (fatal, error, warning, info, debug, trace)")
+	cmd.PersistentFlags().String(optionConvention, "", fmt.Sprintf("Sets the commit convention (%s). It overrides the convention of the configuration file", strings.Join(version.ConventionNames(), ", ")))
+	viper.BindPFlag(optionConvention, cmd.PersistentFlags().Lookup(optionConvention))
+	cmd.PersistentFlags().String(optionGitBackend, "", fmt.Sprintf("Sets the git backend (%s). The go backend reads the repository without the git binary but cannot fetch, commit nor push. It overrides the gitBackend of the configuration file", strings.Join(git.Backends(), ", ")))
+	viper.BindPFlag("gitBackend", cmd.PersistentFlags().Lookup(optionGitBackend))
+	cmd.PersistentFlags().Bool(optionNoFetch, false, "Skips fetching the tags, only the local tags are used. It overrides the fetch.skip of the configuration file")
+	viper.BindPFlag("fetch.skip", cmd.PersistentFlags().Lookup(optionNoFetch))
+	cmd.PersistentFlags().String(optionFetchRemote, "", "Sets the remote the tags are fetched from (default is origin). It overrides the fetch.remote of the configuration file")
+	viper.BindPFlag("fetch.remote", cmd.PersistentFlags().Lookup(optionFetchRemote))
+	cmd.PersistentFlags().String(optionFetchRefspec, "", "Sets the refspec fetched with the tags (default is the one of the remote). It overrides the fetch.refspec of the configuration file")
+	viper.BindPFlag("fetch.refspec", cmd.PersistentFlags().Lookup(optionFetchRefspec))
+	cmd.PersistentFlags().Bool(optionIgnoreFetchErrors, false, "Warns instead of failing when the tags cannot be fetched. It overrides the fetch.ignoreErrors of the configuration file")
+	viper.BindPFlag("fetch.ignoreErrors", cmd.PersistentFlags().Lookup(optionIgnoreFetchErrors))
 
 	dir, err := os.Getwd()
 	if err != nil {
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	historyDesc = `
+This will walk the first-parent history of HEAD and print, for every commit, the version gsemver would have computed
+at that point with the current configuration, alongside the tags actually created on this commit.
+
+The version of a commit is computed with the last tag reachable from its parent, as if the commit was just pushed.
+Commits whose version tags do not match the computed version are marked with a '!'.
+
+This is useful to evaluate a configuration change before rolling it out, for example to check that a new majorPattern
+would not have rewritten your past versions.
+`
+	historyExample = `
+# To print the history of the current branch
+gsemver history
+
+# To print the history since a tag
+gsemver history --from v1.0.0
+
+# To print the history in json
+gsemver history --output json
+
+# To evaluate another configuration
+gsemver history --config new-config.yaml
+`
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// newHistoryCommands create the history command
+func newHistoryCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &historyOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "Print the version computed for every commit of the history",
+		Long:    historyDesc,
+		Example: historyExample,
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addHistoryFlags(cmd)
+
+	return cmd
+}
+
+// historyOptions type to represent the available options for the history command
+// It extends GlobalOptions.
+type historyOptions struct {
+	*globalOptions
+	viperConfig config
+	// From is the revision (excluded) from where to start the history
+	From string
+	// Output is the output format: text or json
+	Output string
+}
+
+func (o *historyOptions) addHistoryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.From, "from", "", "Start the history after this revision (excluded). By default, it starts from the first commit")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", outputText, "Output format: text or json")
+
+	o.Cmd = cmd
+}
+
+func (o *historyOptions) run() error {
+	log.Debug("Run history command with configuration: %#v", o)
+
+	if o.Output != outputText && o.Output != outputJSON {
+		return errors.Errorf("unknown output format %q. Try 'text' or 'json'", o.Output)
+	}
+
+	strategy, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+	entries, err := strategy.History(o.From)
+	if err != nil {
+		return err
+	}
+
+	if o.Output == outputJSON {
+		return writeJSON(o.ioStreams.Out, entries)
+	}
+	return writeHistory(o.ioStreams.Out, entries)
+}
+
+func writeHistory(out io.Writer, entries []version.HistoryEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMIT\tVERSION\tTAGS\tSUBJECT")
+	for _, e := range entries {
+		tags := make([]string, len(e.Tags))
+		for i, tag := range e.Tags {
+			tags[i] = tag.Name
+		}
+		marker := ""
+		if !e.TagsMatch() {
+			marker = " !"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s%s\t%s\n", e.Commit.Hash.Short(), e.Version, strings.Join(tags, ","), marker, subject(e.Commit.Message))
+	}
+	return w.Flush()
+}
+
+func writeJSON(out io.Writer, value interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
+
+// subject returns the first line of a commit message
+func subject(message string) string {
+	return strings.SplitN(message, "\n", 2)[0]
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestWriteHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	entries := []version.HistoryEntry{
+		{
+			Commit:  git.Commit{Hash: git.Hash("1111111111"), Message: "feat: add README.md\n\nwith a body"},
+			Version: version.Version{Minor: 1},
+			Tags:    []git.Tag{{Name: "v0.1.0"}},
+		},
+		{
+			Commit:  git.Commit{Hash: git.Hash("2222222222"), Message: "fix: typo"},
+			Version: version.Version{Minor: 1, Patch: 1},
+			Tags:    []git.Tag{{Name: "v0.2.0"}, {Name: "latest"}},
+		},
+		{
+			Commit:  git.Commit{Hash: git.Hash("3333333333"), Message: "docs: readme"},
+			Version: version.Version{Minor: 2, Patch: 1},
+			Tags:    []git.Tag{},
+		},
+	}
+
+	out := new(bytes.Buffer)
+	assert.NoError(writeHistory(out, entries))
+	assert.Equal(`COMMIT   VERSION  TAGS             SUBJECT
+1111111  0.1.0    v0.1.0           feat: add README.md
+2222222  0.1.1    v0.2.0,latest !  fix: typo
+3333333  0.2.1                     docs: readme
+`, out.String())
+}
+
+func TestHistoryUnknownOutput(t *testing.T) {
+	globalOpts := &globalOptions{
+		ioStreams: newIOStreams(os.Stdin, new(bytes.Buffer), new(bytes.Buffer)),
+	}
+	cmd := newHistoryCommands(globalOpts)
+	globalOpts.addGlobalFlags(cmd)
+
+	_, err := executeCommand(cmd, "--output", "xml")
+	assert.EqualError(t, err, `unknown output format "xml". Try 'text' or 'json'`)
+}
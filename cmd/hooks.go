@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arnaud-deprez/gsemver/internal/git"
+	"github.com/arnaud-deprez/gsemver/internal/log"
+)
+
+const (
+	hooksDesc = `
+This will manage the git hooks of the repository.
+`
+	hooksInstallDesc = `
+This will install a commit-msg hook in the repository that checks every new commit message with 'gsemver lint'.
+The hook is written in the hooks directory of the repository, which takes core.hooksPath into account.
+
+It refuses to overwrite an existing commit-msg hook unless --force is used.
+`
+	hooksInstallExample = `
+# To install the commit-msg hook
+gsemver hooks install
+
+# To install the commit-msg hook using a specific configuration file
+gsemver hooks install --config .gsemver.yaml
+
+# To replace an existing commit-msg hook
+gsemver hooks install --force
+`
+
+	commitMsgHook = "commit-msg"
+)
+
+// newHooksCommands create the hooks command and its sub-commands
+func newHooksCommands(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage the git hooks of the repository",
+		Long:  hooksDesc,
+		Run:   runHelp,
+	}
+
+	cmd.AddCommand(newHooksInstallCommands(globalOpts))
+
+	return cmd
+}
+
+// newHooksInstallCommands create the hooks install command
+func newHooksInstallCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &hooksInstallOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "install",
+		Short:        "Install a commit-msg hook checking the commit messages",
+		Long:         hooksInstallDesc,
+		Example:      hooksInstallExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.Force, "force", false, "Overwrite an existing commit-msg hook")
+
+	return cmd
+}
+
+// hooksInstallOptions type to represent the available options for the hooks install command
+// It extends GlobalOptions.
+type hooksInstallOptions struct {
+	*globalOptions
+	// Force overwrites an existing hook
+	Force bool
+}
+
+func (o *hooksInstallOptions) run() error {
+	log.Debug("Run hooks install command with configuration: %#v", o)
+
+	hooksDir, err := git.GetHooksDir(o.CurrentDir)
+	if err != nil {
+		return errors.Wrapf(err, "cannot find the git hooks directory of %s", o.CurrentDir)
+	}
+
+	hook := filepath.Join(hooksDir, commitMsgHook)
+	if _, err := os.Stat(hook); err == nil && !o.Force {
+		return errors.Errorf("%s already exists, use --force to overwrite it", hook)
+	}
+
+	script, err := o.commitMsgHookScript()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return errors.Wrapf(err, "cannot create %s", hooksDir)
+	}
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		return errors.Wrapf(err, "cannot write %s", hook)
+	}
+
+	fmt.Fprintf(o.ioStreams.Out, "%s hook installed in %s\n", commitMsgHook, hook)
+	return nil
+}
+
+// commitMsgHookScript returns the content of the commit-msg hook
+func (o *hooksInstallOptions) commitMsgHookScript() (string, error) {
+	args := ""
+	if o.ConfigFile != "" {
+		// the hook runs from the top-level directory of the repository
+		configFile, err := filepath.Abs(o.ConfigFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "cannot resolve %s", o.ConfigFile)
+		}
+		// quote the path for the shell, escaping its single quotes
+		args = fmt.Sprintf(" --config '%s'", strings.ReplaceAll(configFile, "'", `'\''`))
+	}
+	return fmt.Sprintf(`#!/bin/sh
+# Installed by 'gsemver hooks install': checks the commit message follows the commit conventions
+exec gsemver lint%s --message-file "$1"
+`, args), nil
+}
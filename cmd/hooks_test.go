@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+)
+
+func TestHooksInstall(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	_, err := command.New("git").InDir(dir).WithArgs("init").Run()
+	assert.NoError(err)
+
+	install := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newHooksCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, append([]string{"install"}, args...)...)
+		return out.String(), err
+	}
+
+	hook := filepath.Join(dir, ".git", "hooks", "commit-msg")
+	out, err := install()
+	assert.NoError(err)
+	assert.Equal("commit-msg hook installed in "+hook+"\n", out)
+
+	content, err := os.ReadFile(hook)
+	assert.NoError(err)
+	assert.Contains(string(content), `exec gsemver lint --message-file "$1"`)
+	info, err := os.Stat(hook)
+	assert.NoError(err)
+	assert.NotZero(info.Mode() & 0100)
+
+	_, err = install()
+	assert.EqualError(err, hook+" already exists, use --force to overwrite it")
+
+	_, err = install("--force", "--config", "/etc/gsemver.yaml")
+	assert.NoError(err)
+	content, err = os.ReadFile(hook)
+	assert.NoError(err)
+	assert.Contains(string(content), `exec gsemver lint --config '/etc/gsemver.yaml' --message-file "$1"`)
+
+	_, err = install("--force", "--config", "/etc/it's/gsemver.yaml")
+	assert.NoError(err)
+	content, err = os.ReadFile(hook)
+	assert.NoError(err)
+	assert.Contains(string(content), `exec gsemver lint --config '/etc/it'\''s/gsemver.yaml' --message-file "$1"`)
+}
+
+func TestHooksInstallOutsideRepository(t *testing.T) {
+	globalOpts := &globalOptions{
+		ioStreams: newIOStreams(os.Stdin, new(bytes.Buffer), new(bytes.Buffer)),
+	}
+	cmd := newHooksCommands(globalOpts)
+	globalOpts.addGlobalFlags(cmd)
+	globalOpts.CurrentDir = t.TempDir()
+
+	_, err := executeCommand(cmd, "install")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot find the git hooks directory")
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	lintDesc = `
+This will check commit messages against the configured conventions, the same ones used to compute the next version.
+It reports every problem found:
+- empty-message: the commit message is empty
+- header-format: the first line does not match the configured commitPattern
+- header-max-length: the first line is longer than 100 characters
+- body-leading-blank: the body is not separated from the first line by a blank line
+
+Merge, revert, fixup and squash messages generated by git are not checked.
+
+By default, it checks the commits since the last tag. With --message-file, it checks a single message instead, which is
+what the commit-msg hook installed by 'gsemver hooks install' does.
+
+The command exits with a non-zero status if any problem is found.
+`
+	lintExample = `
+# To check the commits since the last tag
+gsemver lint
+
+# To check the commits of a feature branch
+gsemver lint --from origin/main
+
+# To check a commit message from a file
+gsemver lint --message-file .git/COMMIT_EDITMSG
+# Or from the standard input
+echo "feat: add lint command" | gsemver lint --message-file -
+`
+
+	// commitMessageScissors is the line below which git ignores everything in a commit message being edited
+	commitMessageScissors = "# ------------------------ >8 ------------------------"
+)
+
+// newLintCommands create the lint command
+func newLintCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &lintOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "lint",
+		Short:        "Check commit messages against the commit conventions",
+		Long:         lintDesc,
+		Example:      lintExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addLintFlags(cmd)
+
+	return cmd
+}
+
+// lintOptions type to represent the available options for the lint command
+// It extends GlobalOptions.
+type lintOptions struct {
+	*globalOptions
+	viperConfig config
+	// From is the revision (excluded) from where to check the commits
+	From string
+	// MessageFile is the file containing the commit message to check, - for the standard input
+	MessageFile string
+	// Output is the output format: text or json
+	Output string
+}
+
+func (o *lintOptions) addLintFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.From, "from", "", "Check the commits after this revision (excluded). By default, it checks the commits since the last tag")
+	cmd.Flags().StringVar(&o.MessageFile, "message-file", "", "Check the commit message of this file instead of the commits. Use - to read the standard input")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", outputText, "Output format: text or json")
+
+	o.Cmd = cmd
+}
+
+func (o *lintOptions) run() error {
+	log.Debug("Run lint command with configuration: %#v", o)
+
+	if o.Output != outputText && o.Output != outputJSON {
+		return errors.Errorf("unknown output format %q. Try 'text' or 'json'", o.Output)
+	}
+
+	strategy, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+
+	var problems []version.LintProblem
+	if o.MessageFile != "" {
+		message, err := o.readMessageFile()
+		if err != nil {
+			return err
+		}
+		problems = strategy.LintMessage(message)
+	} else {
+		problems, err = strategy.Lint(o.From)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeLintProblems(o.ioStreams.Out, o.Output, problems)
+}
+
+// readMessageFile reads the commit message from MessageFile without the lines git ignores
+func (o *lintOptions) readMessageFile() (string, error) {
+	var data []byte
+	var err error
+	if o.MessageFile == "-" {
+		data, err = io.ReadAll(o.ioStreams.In)
+	} else {
+		data, err = os.ReadFile(o.MessageFile)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "cannot read commit message from %s", o.MessageFile)
+	}
+	return cleanCommitMessage(string(data)), nil
+}
+
+// cleanCommitMessage removes the comments and everything below the scissors line as git does when committing
+func cleanCommitMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.TrimRight(line, "\r") == commitMessageScissors {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// writeLintProblems writes the problems in the output format and returns an error if there is any problem
+func writeLintProblems(out io.Writer, output string, problems []version.LintProblem) error {
+	if output == outputJSON {
+		if err := writeJSON(out, problems); err != nil {
+			return err
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Fprintln(out, p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("%d problem(s) found in commit messages", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestCleanCommitMessage(t *testing.T) {
+	message := `feat: add lint command
+
+With a body
+# Please enter the commit message for your changes. Lines starting
+# with '#' will be ignored, and an empty message aborts the commit.
+# ------------------------ >8 ------------------------
+diff --git a/README.md b/README.md
+`
+	assert.Equal(t, "feat: add lint command\n\nWith a body", cleanCommitMessage(message))
+}
+
+func TestLintMessageFile(t *testing.T) {
+	testData := []struct {
+		message  string
+		expected string
+		err      string
+	}{
+		{"feat: add lint command\n", "", ""},
+		{"add lint command\n# comment\n", `header-format: header "add lint command" does not match ` + version.DefaultCommitPattern + "\n", "1 problem(s) found in commit messages"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.message, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			globalOpts := &globalOptions{
+				ioStreams: newIOStreams(strings.NewReader(tc.message), out, new(bytes.Buffer)),
+			}
+			cmd := newLintCommands(globalOpts)
+			globalOpts.addGlobalFlags(cmd)
+
+			_, err := executeCommand(cmd, "--message-file", "-")
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestLintMessageFileNotFound(t *testing.T) {
+	globalOpts := &globalOptions{
+		ioStreams: newIOStreams(strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer)),
+	}
+	cmd := newLintCommands(globalOpts)
+	globalOpts.addGlobalFlags(cmd)
+
+	_, err := executeCommand(cmd, "--message-file", "does-not-exist")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read commit message from does-not-exist")
+}
+
+func TestWriteLintProblems(t *testing.T) {
+	problems := []version.LintProblem{
+		{Commit: "1111111111", Rule: version.LintBodyLeadingBlank, Message: "body must be separated from the header by a blank line"},
+	}
+
+	out := new(bytes.Buffer)
+	err := writeLintProblems(out, outputJSON, problems)
+	assert.EqualError(t, err, "1 problem(s) found in commit messages")
+	assert.Equal(t, `[
+  {
+    "commit": "1111111111",
+    "rule": "body-leading-blank",
+    "message": "body must be separated from the header by a blank line"
+  }
+]
+`, out.String())
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/internal/manifest"
+)
+
+// manifestFiles returns the manifest files of the configuration, their paths are relative to the current directory
+func (o *globalOptions) manifestFiles(c *config) ([]manifest.File, error) {
+	if len(c.Manifests) == 0 {
+		return nil, errors.New("no manifest file configured, add them to the manifests of the configuration file")
+	}
+	files := make([]manifest.File, len(c.Manifests))
+	for i, it := range c.Manifests {
+		path := it.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(o.CurrentDir, path)
+		}
+		files[i] = manifest.File{Path: path, Format: it.Format}
+	}
+	return files, nil
+}
+
+// writeManifests writes the version in the manifest files and returns the files that have changed
+func writeManifests(files []manifest.File, version string) ([]manifest.File, error) {
+	var changed []manifest.File
+	for _, f := range files {
+		ok, err := f.Write(version)
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			log.Info("%s updated to %s", f.Path, version)
+			changed = append(changed, f)
+		}
+	}
+	return changed, nil
+}
+
+// verifyManifests returns an error listing the manifest files whose version is not the version
+func verifyManifests(files []manifest.File, version string) error {
+	var outOfSync []string
+	for _, f := range files {
+		v, err := f.Version()
+		if err != nil {
+			return err
+		}
+		if v != version {
+			outOfSync = append(outOfSync, fmt.Sprintf("%s (%s)", f.Path, v))
+		}
+	}
+	if len(outOfSync) > 0 {
+		return errors.Errorf("manifest files out of sync with version %s: %s", version, strings.Join(outOfSync, ", "))
+	}
+	return nil
+}
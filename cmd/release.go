@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	releaseDesc = `
+This will release the next version: it creates the tag of the version like the tag command and, with --push, pushes it
+to the remote the tags are fetched from, origin by default. It prints the name of the tag.
+
+The tag is pushed atomically. When pipelines release concurrently, they can compute the same version and the remote
+rejects the tag of the last one because it already exists. Then the local tag is deleted, the tags are fetched and the
+version is computed again from the tag pushed in the meantime before tagging and pushing again, up to --push-retries
+times. If the tag pushed in the meantime is on HEAD, HEAD is released already and nothing more is done. With --no-fetch,
+the version cannot be computed again and the release fails as soon as the tag is rejected.
+
+If HEAD already has a version tag, no tag is created and the existing one is pushed, so it can be run again safely.
+
+With --commit, or release.commit in the configuration file, the version is first written in the manifest files of the
+configuration, like 'gsemver bump --write' does, and the changed files are committed with a release commit whose
+message is 'chore(release): <tag>' by default. Then the release commit is tagged. The message template can be set with
+release.commitMessageTemplate in the configuration file. The release commits are ignored to compute the next versions
+and the release notes when release.commit is set in the configuration file: only the commits whose whole message is
+the message template rendered for a version are ignored, unless the releaseCommitPattern of the configuration is set.
+The release commit is not pushed, so --push cannot be used with --commit or release.commit: push the branch and the
+tag together with 'git push --atomic origin HEAD <tag>' instead.
+
+The name and message templates and the signature of the tag are the same as the tag command and can be set in the tag
+section of the configuration file.
+`
+	releaseExample = `
+# To create the tag of the next version and push it
+gsemver release --push
+
+# To see the tag without creating it
+gsemver release --dry-run
+
+# To commit the version of the manifest files and tag the release commit
+gsemver release --commit
+
+# To retry 10 times when concurrent pipelines push the same version
+gsemver release --push --push-retries 10
+`
+)
+
+// newReleaseCommands create the release command
+func newReleaseCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &releaseOptions{
+		tagOptions: &tagOptions{
+			globalOptions: globalOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:          "release",
+		Short:        "Tag the next version and push the tag",
+		Long:         releaseDesc,
+		Example:      releaseExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addReleaseFlags(cmd)
+
+	return cmd
+}
+
+// releaseOptions type to represent the available options for the release command
+// It extends tagOptions.
+type releaseOptions struct {
+	*tagOptions
+	// Push pushes the tag to the remote of the fetch options
+	Push bool
+	// PushRetries is the number of times the version is computed again when the tag is rejected
+	PushRetries int
+	// Commit commits the version of the manifest files before tagging, it is bound to release.commit
+	Commit bool
+}
+
+func (o *releaseOptions) addReleaseFlags(cmd *cobra.Command) {
+	o.addTagFlags(cmd)
+	cmd.Flags().BoolVar(&o.Push, "push", false, "Push the tag to the remote the tags are fetched from (default is origin). It cannot be used with --commit or release.commit of the configuration file")
+	cmd.Flags().IntVar(&o.PushRetries, "push-retries", version.DefaultPushRetries, "Number of times the version is computed again when the remote rejects the tag because it already exists")
+	cmd.Flags().BoolVar(&o.Commit, "commit", false, "Write the version in the manifest files of the configuration and commit them before tagging. It overrides release.commit of the configuration file")
+	cmd.Flags().String("commit-message-template", version.DefaultReleaseCommitTemplate, "Go template of the release commit message. It overrides release.commitMessageTemplate of the configuration file")
+
+	o.Cmd = cmd
+}
+
+func (o *releaseOptions) run() error {
+	log.Debug("Run release command with configuration: %#v", o)
+
+	viper.BindPFlag("release.commit", o.Cmd.Flags().Lookup("commit"))
+	viper.BindPFlag("release.commitMessageTemplate", o.Cmd.Flags().Lookup("commit-message-template"))
+	strategy, opts, err := o.createTagOptions()
+	if err != nil {
+		return err
+	}
+	if o.DryRun {
+		return o.printNextTag(strategy, opts)
+	}
+	if o.viperConfig.Release.Commit {
+		return o.commitRelease(strategy, opts)
+	}
+
+	opts.Push = o.Push
+	opts.PushRetries = o.PushRetries
+	r, created, err := strategy.ReleaseTag(opts)
+	if err != nil {
+		return err
+	}
+	if !created {
+		log.Info("HEAD is already tagged %s", r.Tag.Name)
+	}
+	if o.Push {
+		log.Info("Tag %s pushed", r.Tag.Name)
+	}
+	fmt.Fprintln(o.ioStreams.Out, r.Tag.Name)
+	return nil
+}
+
+// commitRelease writes the version in the manifest files, commits them and tags the release commit
+func (o *releaseOptions) commitRelease(strategy *version.BumpStrategy, opts version.TagOptions) error {
+	if o.Push {
+		return errors.New("--push cannot be used with --commit or release.commit, push the branch and the tag with git push --atomic")
+	}
+	commitTemplate, err := parseTemplate("release commit message", o.viperConfig.Release.CommitMessageTemplate)
+	if err != nil {
+		return err
+	}
+	files, err := o.manifestFiles(&o.viperConfig)
+	if err != nil {
+		return err
+	}
+
+	tag, r, err := strategy.NextTag(opts.NameTemplate, opts.MessageTemplate)
+	if err != nil {
+		return err
+	}
+	if tag == nil {
+		log.Info("HEAD is already tagged %s", r.Tag.Name)
+		fmt.Fprintln(o.ioStreams.Out, r.Tag.Name)
+		return nil
+	}
+
+	r.Tag = tag
+	message, err := strategy.ReleaseCommitMessage(r, commitTemplate)
+	if err != nil {
+		return err
+	}
+	changed, err := writeManifests(files, r.Version.String())
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		paths := make([]string, len(changed))
+		for i, f := range changed {
+			paths[i] = f.Path
+		}
+		if err := strategy.CommitRelease(message, paths...); err != nil {
+			return err
+		}
+	} else {
+		log.Info("The manifest files already have the version %v, HEAD is tagged without release commit", r.Version)
+	}
+
+	if err := strategy.CreateTag(*tag, opts.Sign); err != nil {
+		return err
+	}
+	fmt.Fprintln(o.ioStreams.Out, tag.Name)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+)
+
+func TestReleasePush(t *testing.T) {
+	assert := assert.New(t)
+
+	remote, dir := filepath.Join(t.TempDir(), "remote.git"), t.TempDir()
+	git := func(dir string, args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(args...).Run()
+		assert.NoError(err)
+		return out
+	}
+	git(dir, "init", "--bare", remote)
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git(dir, "init", "--initial-branch", "release/all")
+	// the tagger identity
+	git(dir, "config", "user.name", "gsemver")
+	git(dir, "config", "user.email", "gsemver@example.com")
+	git(dir, "remote", "add", "origin", remote)
+	git(dir, "commit", "--allow-empty", "-m", "feat: initial feature")
+	git(dir, "tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	git(dir, "commit", "--allow-empty", "-m", "fix: handle empty body")
+	git(dir, "push", "origin", "HEAD:refs/heads/main", "v0.1.0")
+
+	release := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newReleaseCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	// the version of the unreleased commits depends on the bump strategies of the configuration
+	out, err := release("--push", "--dry-run")
+	assert.NoError(err)
+	assert.Regexp(`^v0\.1\.\S+\n\nRelease 0\.1\.\S+\n$`, out)
+	assert.Equal("v0.1.0", git(dir, "tag", "--list"))
+
+	out, err = release("--push")
+	assert.NoError(err)
+	name := strings.TrimSpace(out)
+	assert.Regexp(`^v0\.1\.`, name)
+	assert.Equal(git(dir, "rev-parse", "HEAD"), git(remote, "rev-parse", name+"^{commit}"))
+
+	// HEAD is already released
+	out, err = release("--push")
+	assert.NoError(err)
+	assert.Equal(name+"\n", out)
+}
+
+func TestReleaseCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(args...).Run()
+		assert.NoError(err)
+		return out
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	git("config", "user.name", "gsemver")
+	git("config", "user.email", "gsemver@example.com")
+	packageJSON := filepath.Join(dir, "package.json")
+	assert.NoError(os.WriteFile(packageJSON, []byte("{\n  \"name\": \"app\",\n  \"version\": \"0.1.0\"\n}\n"), 0644))
+	git("add", "package.json")
+	git("commit", "-m", "feat: initial feature")
+	git("tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	git("commit", "--allow-empty", "-m", "fix: handle empty body")
+	// a change not part of the release commit
+	assert.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte("# app\n"), 0644))
+	git("add", "README.md")
+
+	viper.Set("manifests", []map[string]interface{}{{"path": "package.json"}})
+	t.Cleanup(func() { viper.Set("manifests", nil) })
+
+	release := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newReleaseCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+
+	// the release commit is not pushed
+	_, err := release("--commit", "--push")
+	assert.EqualError(err, "--push cannot be used with --commit or release.commit, push the branch and the tag with git push --atomic")
+	viper.Set("release.commit", true)
+	_, err = release("--push")
+	viper.Set("release.commit", nil)
+	assert.EqualError(err, "--push cannot be used with --commit or release.commit, push the branch and the tag with git push --atomic")
+	// only the version can change in the release commit messages
+	_, err = release("--commit", "--commit-message-template", "chore(release): {{.Tag.Name}} on {{.Date.Year}}")
+	assert.ErrorContains(err, "does not match the release commit pattern")
+	assert.Equal("A  README.md", git("status", "--porcelain"))
+
+	// the version depends on the bump strategies of the configuration
+	out, err := release("--commit")
+	assert.NoError(err)
+	name := strings.TrimSpace(out)
+	v := strings.TrimPrefix(name, "v")
+	assert.Regexp(`^v0\.1\.`, name)
+	assert.Equal("chore(release): "+name, git("log", "-1", "--format=%s"))
+	assert.Equal("package.json", git("show", "--format=", "--name-only", "HEAD"))
+	assert.Equal(git("rev-parse", "HEAD"), git("rev-parse", name+"^{commit}"))
+	content, err := os.ReadFile(packageJSON)
+	assert.NoError(err)
+	assert.Equal("{\n  \"name\": \"app\",\n  \"version\": \""+v+"\"\n}\n", string(content))
+	assert.Equal("A  README.md", git("status", "--porcelain"))
+
+	// HEAD is already released
+	out, err = release("--commit")
+	assert.NoError(err)
+	assert.Equal(name+"\n", out)
+}
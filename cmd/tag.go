@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	tagDesc = `
+This will compute the next version like the bump command and create an annotated tag for it on HEAD.
+It prints the name of the tag.
+
+The name and the message of the tag are go templates using sprig functions (http://masterminds.github.io/sprig/).
+They are applied to the release notes of the version, like the templates of the changelog command, so the message can
+list the changes and the issues of the version. The name defaults to v{{.Version}} and can add a prefix such as the path
+of a go module in a sub-directory (tools/v{{.Version}}). It must end with the version so that gsemver finds it back to
+compute the next versions.
+
+The templates and the signature can be set in the tag section of the configuration file:
+
+tag:
+  nameTemplate: "v{{.Version}}"
+  messageTemplate: "Release {{.Version}}"
+  sign: true
+
+With --sign, the tag is signed with the gpg key of the tagger as git tag --sign does.
+
+If HEAD already has a version tag, no tag is created and the existing one is printed, so it can be run again safely.
+`
+	tagExample = `
+# To create the tag of the next version
+gsemver tag
+
+# To see the tag without creating it
+gsemver tag --dry-run
+
+# To tag a go module in a sub-directory
+gsemver tag --name-template "tools/v{{.Version}}"
+
+# To create a signed tag with the release notes as message
+gsemver tag --sign --message-template "$(cat .github/release-notes.tmpl)"
+`
+)
+
+// newTagCommands create the tag command
+func newTagCommands(globalOpts *globalOptions) *cobra.Command {
+	options := &tagOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:          "tag",
+		Short:        "Create the tag of the next version",
+		Long:         tagDesc,
+		Example:      tagExample,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.configureLogger()
+
+			options.Cmd = cmd
+			options.Args = args
+			return options.run()
+		},
+	}
+
+	options.addTagFlags(cmd)
+
+	return cmd
+}
+
+// tagOptions type to represent the available options for the tag command
+// It extends GlobalOptions.
+type tagOptions struct {
+	*globalOptions
+	viperConfig config
+	// DryRun prints the tag without creating it
+	DryRun bool
+}
+
+func (o *tagOptions) addTagFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name-template", version.DefaultTagNameTemplate, "Go template of the tag name. It overrides tag.nameTemplate of the configuration file")
+	cmd.Flags().String("message-template", version.DefaultTagMessageTemplate, "Go template of the tag message. It overrides tag.messageTemplate of the configuration file")
+	cmd.Flags().Bool("sign", false, "Sign the tag with the gpg key of the tagger. It overrides tag.sign of the configuration file")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Print the tag name and message without creating the tag")
+
+	o.Cmd = cmd
+}
+
+func (o *tagOptions) run() error {
+	log.Debug("Run tag command with configuration: %#v", o)
+
+	strategy, opts, err := o.createTagOptions()
+	if err != nil {
+		return err
+	}
+	if o.DryRun {
+		return o.printNextTag(strategy, opts)
+	}
+
+	r, created, err := strategy.ReleaseTag(opts)
+	if err != nil {
+		return err
+	}
+	if !created {
+		log.Info("HEAD is already tagged %s", r.Tag.Name)
+	}
+	fmt.Fprintln(o.ioStreams.Out, r.Tag.Name)
+	return nil
+}
+
+// createTagOptions creates the BumpStrategy and the TagOptions from the configuration, overridden by the flags of the command
+func (o *tagOptions) createTagOptions() (*version.BumpStrategy, version.TagOptions, error) {
+	// the flags are bound when the command runs as the tag flags are shared by several commands
+	viper.BindPFlag("tag.nameTemplate", o.Cmd.Flags().Lookup("name-template"))
+	viper.BindPFlag("tag.messageTemplate", o.Cmd.Flags().Lookup("message-template"))
+	viper.BindPFlag("tag.sign", o.Cmd.Flags().Lookup("sign"))
+
+	opts := version.TagOptions{}
+	strategy, err := o.createBumpStrategyFromConfig(&o.viperConfig)
+	if err != nil {
+		return nil, opts, err
+	}
+	if opts.NameTemplate, err = parseTemplate("tag name", o.viperConfig.Tag.NameTemplate); err != nil {
+		return nil, opts, err
+	}
+	if opts.MessageTemplate, err = parseTemplate("tag message", o.viperConfig.Tag.MessageTemplate); err != nil {
+		return nil, opts, err
+	}
+	opts.Sign = o.viperConfig.Tag.Sign
+	return strategy, opts, nil
+}
+
+// printNextTag prints the name and the message of the tag of the next version without creating it
+func (o *tagOptions) printNextTag(strategy *version.BumpStrategy, opts version.TagOptions) error {
+	tag, r, err := strategy.NextTag(opts.NameTemplate, opts.MessageTemplate)
+	if err != nil {
+		return err
+	}
+	if tag == nil {
+		log.Info("HEAD is already tagged %s", r.Tag.Name)
+		fmt.Fprintln(o.ioStreams.Out, r.Tag.Name)
+		return nil
+	}
+	fmt.Fprintf(o.ioStreams.Out, "%s\n\n%s\n", tag.Name, tag.Message)
+	return nil
+}
+
+// parseTemplate parses the go template of an option
+func parseTemplate(option string, text string) (tmpl *template.Template, err error) {
+	if text == "" {
+		return nil, errors.Errorf("%s template is empty", option)
+	}
+	// utils.NewTemplate panics on an invalid template
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("invalid %s template: %v", option, r)
+		}
+	}()
+	return utils.NewTemplate(text), nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+)
+
+func TestTag(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err)
+		return out
+	}
+	// a release branch for the default bump strategies and the ones of TestWithConfiguration
+	git("init", "--initial-branch", "release/all")
+	// the tagger identity
+	git("config", "user.name", "gsemver")
+	git("config", "user.email", "gsemver@example.com")
+	git("commit", "--allow-empty", "-m", "feat: initial feature")
+	git("tag", "v0.1.0")
+	git("commit", "--allow-empty", "-m", "fix(parser): handle empty body (#4)")
+
+	tag := func(args ...string) (string, error) {
+		out := new(bytes.Buffer)
+		globalOpts := &globalOptions{
+			ioStreams: newIOStreams(os.Stdin, out, new(bytes.Buffer)),
+		}
+		cmd := newTagCommands(globalOpts)
+		globalOpts.addGlobalFlags(cmd)
+		globalOpts.CurrentDir = dir
+		_, err := executeCommand(cmd, args...)
+		return out.String(), err
+	}
+	messageTemplate := "## {{.Version}}\n{{range .Issues}}\n* {{.Ref}}{{end}}"
+
+	// the version of the unreleased commits depends on the bump strategies of the configuration
+	out, err := tag("--dry-run", "--name-template", "tools/v{{.Version}}", "--message-template", messageTemplate)
+	assert.NoError(err)
+	assert.Regexp(`^tools/v0\.1\.\S+\n\n## 0\.1\.\S+\n\n\* #4\n$`, out)
+	assert.Equal("v0.1.0", git("tag", "--list"))
+
+	_, err = tag("--name-template", "{{.Version")
+	assert.ErrorContains(err, "invalid tag name template: ")
+	_, err = tag("--message-template", "")
+	assert.EqualError(err, "tag message template is empty")
+	_, err = tag("--name-template", "release")
+	assert.ErrorContains(err, "Tag name 'release' does not end with the version 0.1.")
+
+	out, err = tag("--name-template", "tools/v{{.Version}}", "--message-template", messageTemplate)
+	assert.NoError(err)
+	name := strings.TrimSpace(out)
+	assert.Regexp(`^tools/v0\.1\.`, name)
+	// the tag is annotated and its markdown message is kept
+	assert.Equal("tag", git("cat-file", "-t", name))
+	assert.Equal("## "+strings.TrimPrefix(name, "tools/v")+"\n\n* #4", git("tag", "--list", "--format=%(contents)", name))
+
+	// HEAD is already tagged
+	out, err = tag()
+	assert.NoError(err)
+	assert.Equal(name+"\n", out)
+}
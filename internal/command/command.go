@@ -198,6 +198,10 @@ func (c *Command) RunWithContext(ctx *context.Context) (string, error) {
 		e.Env = envVars
 	}
 
+	if c.In != nil {
+		e.Stdin = c.In
+	}
+
 	if c.Out != nil {
 		e.Stdout = c.Out
 	}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+// Config is the representation of a gsemver configuration file
+type Config struct {
+	// Convention is the name of the commit convention
+	Convention string `yaml:"convention,omitempty"`
+	// MajorPattern overrides the major pattern of the convention
+	MajorPattern string `yaml:"majorPattern,omitempty"`
+	// MinorPattern overrides the minor pattern of the convention
+	MinorPattern string `yaml:"minorPattern,omitempty"`
+	// PatchPattern overrides the patch pattern of the convention
+	PatchPattern string `yaml:"patchPattern,omitempty"`
+	// Scopes are the scope rules
+	Scopes *Scopes `yaml:"scopes,omitempty"`
+	// BumpStrategies are the bump strategies applied in order to the matching branches
+	BumpStrategies []BumpStrategy `yaml:"bumpStrategies"`
+}
+
+// Scopes is the representation of the scope rules
+type Scopes struct {
+	// Ignore is the list of scopes whose commits never trigger a bump
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+// BumpStrategy is the representation of a bump strategy for matching branches
+type BumpStrategy struct {
+	BranchesPattern       string `yaml:"branchesPattern"`
+	Strategy              string `yaml:"strategy"`
+	PreRelease            bool   `yaml:"preRelease,omitempty"`
+	PreReleaseTemplate    string `yaml:"preReleaseTemplate,omitempty"`
+	BuildMetadataTemplate string `yaml:"buildMetadataTemplate,omitempty"`
+}
+
+// Marshal encodes the configuration in YAML
+func (c *Config) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Warnings collects what cannot be mapped during an import
+type Warnings []string
+
+// add adds a warning
+func (w *Warnings) add(format string, args ...interface{}) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// defaultBuildBumpStrategy is the strategy gsemver uses by default for non release branches
+func defaultBuildBumpStrategy() BumpStrategy {
+	return BumpStrategy{BranchesPattern: ".*", Strategy: "AUTO", BuildMetadataTemplate: version.DefaultBuildMetadataTemplate}
+}
+
+// commitPattern returns the regular expression matching a commit header with the type and the scope.
+// An empty type or scope matches any type or scope.
+func commitPattern(commitType string, scope string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	if commitType == "" {
+		sb.WriteString(`[\w-]+`)
+	} else {
+		sb.WriteString(regexp.QuoteMeta(commitType))
+	}
+	if scope == "" {
+		sb.WriteString(`(?:\(.+\))?`)
+	} else {
+		sb.WriteString(`\(` + regexp.QuoteMeta(scope) + `\)`)
+	}
+	sb.WriteString(`!?: .+`)
+	return sb.String()
+}
+
+// joinPatterns returns a regular expression matching any of the patterns
+func joinPatterns(patterns ...string) string {
+	var groups []string
+	for _, p := range patterns {
+		if p != "" {
+			groups = append(groups, "(?:"+p+")")
+		}
+	}
+	if len(groups) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(groups[0], "(?:"), ")")
+	}
+	return strings.Join(groups, "|")
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	// releasePleaseMinorPattern matches the commits release-please releases as a minor change
+	releasePleaseMinorPattern = `^feat(?:\(.+\))?!?: .+`
+	// releasePleaseDefaultPrereleaseType is the pre-release identifier used when prerelease-type is not defined
+	releasePleaseDefaultPrereleaseType = "beta"
+)
+
+var (
+	// releasePleasePatchTypes are the commit types of the default visible changelog sections, except feat
+	releasePleasePatchTypes = []string{"fix", "perf", "revert", "deps"}
+)
+
+/*
+ImportReleasePlease translates a release-please configuration (release-please-config.json) into a gsemver configuration.
+
+The options of the root package override the top level ones.
+The commit types of the visible changelog sections trigger a release, feat as a minor change and the others as
+a patch change. The release branches produce pre-releases if the prerelease option is enabled.
+Everything that cannot be mapped is returned as warnings.
+*/
+func ImportReleasePlease(data []byte) (*Config, Warnings, error) {
+	var rp map[string]interface{}
+	if err := yaml.Unmarshal(data, &rp); err != nil {
+		return nil, nil, fmt.Errorf("cannot parse release-please configuration: %v", err)
+	}
+
+	var warnings Warnings
+	options := map[string]interface{}{}
+	for k, v := range rp {
+		if k != "packages" && k != "$schema" {
+			options[k] = v
+		}
+	}
+	if packages, ok := rp["packages"].(map[string]interface{}); ok {
+		var ignored []string
+		for path, p := range packages {
+			if path != "." {
+				ignored = append(ignored, path)
+				continue
+			}
+			if root, ok := p.(map[string]interface{}); ok {
+				for k, v := range root {
+					options[k] = v
+				}
+			}
+		}
+		if len(ignored) > 0 {
+			sort.Strings(ignored)
+			warnings.add("ignored packages, gsemver versions the repository as a whole: %s", strings.Join(ignored, ", "))
+		}
+	}
+
+	ret := &Config{
+		Convention:   version.ConventionalCommits,
+		MinorPattern: releasePleaseMinorPattern,
+		PatchPattern: releasePleasePatchPattern(options, &warnings),
+	}
+	release := BumpStrategy{BranchesPattern: version.DefaultReleaseBranchesPattern, Strategy: "AUTO"}
+	if options["prerelease"] == true {
+		release.PreRelease = true
+		release.PreReleaseTemplate = releasePleaseDefaultPrereleaseType
+		if t, ok := options["prerelease-type"].(string); ok && t != "" {
+			release.PreReleaseTemplate = t
+		}
+	}
+	ret.BumpStrategies = []BumpStrategy{release, defaultBuildBumpStrategy()}
+
+	if options["bump-minor-pre-major"] != true {
+		warnings.add("bump-minor-pre-major is not enabled but gsemver always bumps the minor version for breaking changes before 1.0.0")
+	}
+	if options["bump-patch-for-minor-pre-major"] == true {
+		warnings.add("bump-patch-for-minor-pre-major is ignored, gsemver bumps the minor version for features before 1.0.0")
+	}
+	if v, ok := options["versioning"]; ok && v != "default" {
+		warnings.add("versioning %v is ignored, gsemver uses the default versioning", v)
+	}
+	if v, ok := options["release-as"]; ok {
+		warnings.add("release-as %v is ignored, use a %s footer instead", v, version.ReleaseAsToken)
+	}
+	if unknown := unknownKeys(options, "bump-minor-pre-major", "bump-patch-for-minor-pre-major", "prerelease",
+		"prerelease-type", "versioning", "release-as", "changelog-sections"); len(unknown) > 0 {
+		warnings.add("ignored options: %s", strings.Join(unknown, ", "))
+	}
+	return ret, warnings, nil
+}
+
+// releasePleasePatchPattern returns the pattern of the commit types of the visible changelog sections, except feat
+func releasePleasePatchPattern(options map[string]interface{}, warnings *Warnings) string {
+	types := releasePleasePatchTypes
+	if sections, ok := options["changelog-sections"].([]interface{}); ok {
+		types = nil
+		for _, s := range sections {
+			section, _ := s.(map[string]interface{})
+			t, _ := section["type"].(string)
+			if t == "" {
+				warnings.add("changelog section %v is ignored, it has no type", s)
+				continue
+			}
+			if section["hidden"] != true && t != "feat" {
+				types = append(types, regexp.QuoteMeta(t))
+			}
+		}
+	}
+	if len(types) == 0 {
+		// a pattern that never matches: only features and breaking changes trigger a release
+		return `[^\s\S]`
+	}
+	return `^(?:` + strings.Join(types, "|") + `)(?:\(.+\))?!?: .+`
+}
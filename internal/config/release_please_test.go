@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestImportReleasePlease(t *testing.T) {
+	assert := assert.New(t)
+
+	c, warnings, err := ImportReleasePlease([]byte(`{
+  "$schema": "https://raw.githubusercontent.com/googleapis/release-please/main/schemas/config.json",
+  "release-type": "go",
+  "bump-minor-pre-major": true,
+  "packages": {
+    ".": {
+      "prerelease": true,
+      "prerelease-type": "rc",
+      "changelog-sections": [
+        {"type": "feat", "section": "Features"},
+        {"type": "fix", "section": "Bug Fixes"},
+        {"type": "docs", "section": "Documentation"},
+        {"type": "chore", "section": "Miscellaneous", "hidden": true}
+      ]
+    },
+    "tools/generator": {}
+  }
+}`))
+	assert.NoError(err)
+	assert.Equal(&Config{
+		Convention:   version.ConventionalCommits,
+		MinorPattern: releasePleaseMinorPattern,
+		PatchPattern: `^(?:fix|docs)(?:\(.+\))?!?: .+`,
+		BumpStrategies: []BumpStrategy{
+			{BranchesPattern: version.DefaultReleaseBranchesPattern, Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "rc"},
+			defaultBuildBumpStrategy(),
+		},
+	}, c)
+	assert.Equal(Warnings{
+		"ignored packages, gsemver versions the repository as a whole: tools/generator",
+		"ignored options: release-type",
+	}, warnings)
+}
+
+func TestImportReleasePleaseDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	c, warnings, err := ImportReleasePlease([]byte(`{
+  "bump-patch-for-minor-pre-major": true,
+  "versioning": "always-bump-patch",
+  "release-as": "2.0.0",
+  "packages": {".": {}}
+}`))
+	assert.NoError(err)
+	assert.Equal(`^(?:fix|perf|revert|deps)(?:\(.+\))?!?: .+`, c.PatchPattern)
+	assert.Equal([]BumpStrategy{
+		{BranchesPattern: version.DefaultReleaseBranchesPattern, Strategy: "AUTO"},
+		defaultBuildBumpStrategy(),
+	}, c.BumpStrategies)
+	assert.Equal(Warnings{
+		"bump-minor-pre-major is not enabled but gsemver always bumps the minor version for breaking changes before 1.0.0",
+		"bump-patch-for-minor-pre-major is ignored, gsemver bumps the minor version for features before 1.0.0",
+		"versioning always-bump-patch is ignored, gsemver uses the default versioning",
+		"release-as 2.0.0 is ignored, use a Release-As footer instead",
+	}, warnings)
+}
+
+func TestConfigMarshal(t *testing.T) {
+	c := &Config{
+		Convention: version.ConventionalCommits,
+		Scopes:     &Scopes{Ignore: []string{"deps"}},
+		BumpStrategies: []BumpStrategy{
+			{BranchesPattern: "^main$", Strategy: "AUTO"},
+			{BranchesPattern: "^beta$", Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "beta"},
+		},
+	}
+	out, err := c.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, `convention: conventional
+scopes:
+  ignore:
+    - deps
+bumpStrategies:
+  - branchesPattern: ^main$
+    strategy: AUTO
+  - branchesPattern: ^beta$
+    strategy: AUTO
+    preRelease: true
+    preReleaseTemplate: beta
+`, string(out))
+}
@@ -0,0 +1,323 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	// commitAnalyzerPlugin is the semantic-release plugin computing the release type from the commits
+	commitAnalyzerPlugin = "@semantic-release/commit-analyzer"
+	// maintenanceBranchesGlob is the default semantic-release glob of the maintenance branches such as 1.x or 1.2.x
+	maintenanceBranchesGlob = "+([0-9])?(.{+([0-9]),x}).x"
+)
+
+var (
+	// semanticReleaseDefaultBranches are the branches semantic-release uses when none are configured
+	semanticReleaseDefaultBranches = []interface{}{
+		maintenanceBranchesGlob,
+		"master",
+		"main",
+		"next",
+		"next-major",
+		map[string]interface{}{"name": "beta", "prerelease": true},
+		map[string]interface{}{"name": "alpha", "prerelease": true},
+	}
+	// semanticReleasePresets maps the semantic-release presets to the gsemver conventions
+	semanticReleasePresets = map[string]string{
+		"angular":             version.Angular,
+		"conventionalcommits": version.ConventionalCommits,
+		"eslint":              version.ESLint,
+		"atom":                version.Atom,
+		"jquery":              version.JQuery,
+	}
+	/* const */ extglobRegex = regexp.MustCompile(`[+@!?*]\(|[{}\[\]]`)
+)
+
+/*
+ImportSemanticRelease translates a semantic-release configuration (.releaserc in JSON or YAML, or a package.json
+with a release key) into a gsemver configuration.
+
+The branches become bump strategies: the release and maintenance branches bump the version from the commits and
+the pre-release branches produce pre-releases named after the prerelease option.
+The preset and the release rules of the commit analyzer select the convention and extend its patterns.
+Everything that cannot be mapped is returned as warnings.
+*/
+func ImportSemanticRelease(data []byte) (*Config, Warnings, error) {
+	var sr map[string]interface{}
+	if err := yaml.Unmarshal(data, &sr); err != nil {
+		return nil, nil, fmt.Errorf("cannot parse semantic-release configuration: %v", err)
+	}
+	// package.json holds the configuration in the release key
+	if release, ok := sr["release"].(map[string]interface{}); ok {
+		sr = release
+	}
+
+	var warnings Warnings
+	ret := &Config{Convention: version.Angular}
+
+	branches, ok := sr["branches"].([]interface{})
+	if !ok {
+		if branch, isString := sr["branches"].(string); isString {
+			branches = []interface{}{branch}
+		} else {
+			branches = semanticReleaseDefaultBranches
+		}
+	}
+	for _, b := range branches {
+		if s, ok := importSemanticReleaseBranch(b, &warnings); ok {
+			ret.BumpStrategies = append(ret.BumpStrategies, s)
+		}
+	}
+	ret.BumpStrategies = append(ret.BumpStrategies, defaultBuildBumpStrategy())
+
+	// the commit analyzer options can be defined globally or on the plugin
+	options := map[string]interface{}{}
+	for _, key := range []string{"preset", "releaseRules", "parserOpts", "presetConfig"} {
+		if v, ok := sr[key]; ok {
+			options[key] = v
+		}
+	}
+	var ignoredPlugins []string
+	for _, p := range asSlice(sr["plugins"]) {
+		name, pluginOptions := semanticReleasePlugin(p)
+		if name != commitAnalyzerPlugin {
+			ignoredPlugins = append(ignoredPlugins, name)
+			continue
+		}
+		for k, v := range pluginOptions {
+			options[k] = v
+		}
+	}
+	if len(ignoredPlugins) > 0 {
+		warnings.add("ignored plugins, gsemver only computes the version: %s", strings.Join(ignoredPlugins, ", "))
+	}
+	if err := importCommitAnalyzer(ret, options, &warnings); err != nil {
+		return nil, nil, err
+	}
+
+	if tagFormat, ok := sr["tagFormat"].(string); ok && tagFormat != "v${version}" {
+		warnings.add("tagFormat %q is ignored, gsemver reads the semver tags", tagFormat)
+	}
+	if unknown := unknownKeys(sr, "branches", "plugins", "tagFormat", "preset", "releaseRules", "parserOpts", "presetConfig"); len(unknown) > 0 {
+		warnings.add("ignored options: %s", strings.Join(unknown, ", "))
+	}
+	return ret, warnings, nil
+}
+
+// importSemanticReleaseBranch translates a semantic-release branch definition into a bump strategy
+func importSemanticReleaseBranch(branch interface{}, warnings *Warnings) (BumpStrategy, bool) {
+	def, ok := branch.(map[string]interface{})
+	if !ok {
+		def = map[string]interface{}{"name": branch}
+	}
+	name, _ := def["name"].(string)
+	if name == "" {
+		warnings.add("branch %v is ignored, it has no name", branch)
+		return BumpStrategy{}, false
+	}
+	pattern, ok := globToPattern(name)
+	if !ok {
+		warnings.add("branch %q is ignored, its glob cannot be translated to a regular expression", name)
+		return BumpStrategy{}, false
+	}
+	ret := BumpStrategy{BranchesPattern: pattern, Strategy: "AUTO"}
+
+	if r, ok := def["range"].(string); ok {
+		warnings.add("range %q of branch %q is not enforced", r, name)
+	} else if name == maintenanceBranchesGlob {
+		warnings.add("maintenance branches %q are not restricted to their version range", name)
+	}
+	if channel, ok := def["channel"]; ok && channel != false && channel != "" {
+		warnings.add("channel %v of branch %q is ignored, gsemver does not manage distribution channels", channel, name)
+	}
+
+	switch prerelease := def["prerelease"].(type) {
+	case bool:
+		if prerelease {
+			ret.PreRelease = true
+			ret.PreReleaseTemplate = name
+			if pattern != "^"+regexp.QuoteMeta(name)+"$" {
+				ret.PreReleaseTemplate = "{{.Branch}}"
+			}
+		}
+	case string:
+		ret.PreRelease = true
+		ret.PreReleaseTemplate = strings.ReplaceAll(prerelease, "${name}", "{{.Branch}}")
+		if strings.Contains(ret.PreReleaseTemplate, "${") {
+			warnings.add("prerelease %q of branch %q cannot be translated, the branch name is used instead", prerelease, name)
+			ret.PreReleaseTemplate = "{{.Branch}}"
+		}
+	}
+	return ret, true
+}
+
+// importCommitAnalyzer translates the options of the commit analyzer into the convention and its patterns
+func importCommitAnalyzer(c *Config, options map[string]interface{}, warnings *Warnings) error {
+	if preset, ok := options["preset"].(string); ok {
+		if convention, ok := semanticReleasePresets[strings.ToLower(preset)]; ok {
+			c.Convention = convention
+		} else {
+			warnings.add("preset %q has no equivalent convention, %s is used instead", preset, c.Convention)
+		}
+	}
+	for _, key := range []string{"parserOpts", "presetConfig"} {
+		if _, ok := options[key]; ok {
+			warnings.add("commit analyzer option %s is ignored", key)
+		}
+	}
+
+	rules := asSlice(options["releaseRules"])
+	if len(rules) == 0 {
+		if s, ok := options["releaseRules"].(string); ok {
+			warnings.add("release rules module %q cannot be imported", s)
+		}
+		return nil
+	}
+	convention, err := version.GetConvention(c.Convention)
+	if err != nil {
+		return err
+	}
+	levels := map[string]*[]string{"major": {}, "minor": {}, "patch": {}}
+	for _, r := range rules {
+		importReleaseRule(c, convention, r, levels, warnings)
+	}
+	if len(*levels["major"]) > 0 {
+		c.MajorPattern = joinPatterns(append([]string{convention.MajorPattern}, *levels["major"]...)...)
+	}
+	if len(*levels["minor"]) > 0 {
+		c.MinorPattern = joinPatterns(append([]string{convention.MinorPattern}, *levels["minor"]...)...)
+	}
+	// without patch pattern, any other commit is already a patch change
+	if len(*levels["patch"]) > 0 && convention.PatchPattern != "" {
+		c.PatchPattern = joinPatterns(append([]string{convention.PatchPattern}, *levels["patch"]...)...)
+	}
+	return nil
+}
+
+// importReleaseRule translates a release rule into a pattern of its release level or an ignored scope
+func importReleaseRule(c *Config, convention version.Convention, rule interface{}, levels map[string]*[]string, warnings *Warnings) {
+	def, ok := rule.(map[string]interface{})
+	if !ok {
+		warnings.add("release rule %v is ignored", rule)
+		return
+	}
+	commitType, _ := def["type"].(string)
+	scope, _ := def["scope"].(string)
+	if unknown := unknownKeys(def, "type", "scope", "release", "breaking"); len(unknown) > 0 {
+		warnings.add("release rule %v is ignored, gsemver cannot match %s", def, strings.Join(unknown, ", "))
+		return
+	}
+	if breaking, ok := def["breaking"]; ok {
+		if breaking != true || def["release"] != "major" {
+			warnings.add("release rule %v is ignored, breaking changes always trigger a major release", def)
+		}
+		return
+	}
+	if extglobRegex.MatchString(commitType) || extglobRegex.MatchString(scope) {
+		warnings.add("release rule %v is ignored, globs are not supported", def)
+		return
+	}
+
+	switch release := def["release"]; release {
+	case false:
+		if commitType != "" || scope == "" {
+			warnings.add("release rule %v is ignored, only scopes can be excluded from the release", def)
+			return
+		}
+		if c.Scopes == nil {
+			c.Scopes = &Scopes{}
+		}
+		c.Scopes.Ignore = append(c.Scopes.Ignore, scope)
+	case "major", "minor", "patch":
+		if commitType == "" && scope == "" {
+			warnings.add("release rule %v is ignored, it matches no commit", def)
+			return
+		}
+		// the patterns of the convention are evaluated first so they cannot be lowered
+		header := commitType + ": message"
+		if scope != "" {
+			header = commitType + "(" + scope + "): message"
+		}
+		if release != "major" && matches(convention.MajorPattern, header) || release == "patch" && matches(convention.MinorPattern, header) {
+			warnings.add("release rule %v is ignored, it cannot lower the release of the %s convention", def, convention.Name)
+			return
+		}
+		patterns := levels[release.(string)]
+		*patterns = append(*patterns, commitPattern(commitType, scope))
+	default:
+		warnings.add("release rule %v is ignored, release %v is not supported", def, release)
+	}
+}
+
+// semanticReleasePlugin returns the name and options of a plugin defined as "name" or ["name", {options}]
+func semanticReleasePlugin(plugin interface{}) (string, map[string]interface{}) {
+	if def := asSlice(plugin); len(def) > 0 {
+		name, _ := def[0].(string)
+		if len(def) > 1 {
+			options, _ := def[1].(map[string]interface{})
+			return name, options
+		}
+		return name, nil
+	}
+	name, _ := plugin.(string)
+	return name, nil
+}
+
+// globToPattern translates a branch glob into a regular expression.
+// It supports *, ** and ? as well as the default maintenance branches glob.
+func globToPattern(glob string) (string, bool) {
+	if glob == maintenanceBranchesGlob {
+		return `^\d+(?:\.(?:\d+|x))?\.x$`, true
+	}
+	if extglobRegex.MatchString(glob) {
+		return "", false
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			sb.WriteString("[^/]*")
+		case glob[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String(), true
+}
+
+// matches returns true if the pattern is defined and matches the value
+func matches(pattern string, value string) bool {
+	return pattern != "" && regexp.MustCompile(pattern).MatchString(value)
+}
+
+// unknownKeys returns the sorted keys of the map that are not known
+func unknownKeys(m map[string]interface{}, known ...string) []string {
+	var ret []string
+	for key := range m {
+		if !utils.ContainsString(known, key) {
+			ret = append(ret, key)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// asSlice returns the value if it is a list, nil otherwise
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
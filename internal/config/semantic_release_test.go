@@ -0,0 +1,137 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestImportSemanticReleaseBranches(t *testing.T) {
+	assert := assert.New(t)
+
+	c, warnings, err := ImportSemanticRelease([]byte(`{
+  "branches": [
+    "+([0-9])?(.{+([0-9]),x}).x",
+    "main",
+    {"name": "2.x", "range": "2.x", "channel": "2.x"},
+    {"name": "next", "channel": "next"},
+    {"name": "beta", "prerelease": true},
+    {"name": "pre/*", "prerelease": "rc-${name}"},
+    {"name": "alpha", "prerelease": "${name.toUpperCase()}"},
+    "+(feature|fix)/*"
+  ]
+}`))
+	assert.NoError(err)
+	assert.Equal(version.Angular, c.Convention)
+	assert.Equal([]BumpStrategy{
+		{BranchesPattern: `^\d+(?:\.(?:\d+|x))?\.x$`, Strategy: "AUTO"},
+		{BranchesPattern: `^main$`, Strategy: "AUTO"},
+		{BranchesPattern: `^2\.x$`, Strategy: "AUTO"},
+		{BranchesPattern: `^next$`, Strategy: "AUTO"},
+		{BranchesPattern: `^beta$`, Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "beta"},
+		{BranchesPattern: `^pre/[^/]*$`, Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "rc-{{.Branch}}"},
+		{BranchesPattern: `^alpha$`, Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "{{.Branch}}"},
+		defaultBuildBumpStrategy(),
+	}, c.BumpStrategies)
+	assert.Equal(Warnings{
+		`maintenance branches "+([0-9])?(.{+([0-9]),x}).x" are not restricted to their version range`,
+		`range "2.x" of branch "2.x" is not enforced`,
+		`channel 2.x of branch "2.x" is ignored, gsemver does not manage distribution channels`,
+		`channel next of branch "next" is ignored, gsemver does not manage distribution channels`,
+		`prerelease "${name.toUpperCase()}" of branch "alpha" cannot be translated, the branch name is used instead`,
+		`branch "+(feature|fix)/*" is ignored, its glob cannot be translated to a regular expression`,
+	}, warnings)
+
+	maintenance := regexp.MustCompile(c.BumpStrategies[0].BranchesPattern)
+	for _, branch := range []string{"1.x", "1.2.x", "1.x.x"} {
+		assert.True(maintenance.MatchString(branch), branch)
+	}
+	assert.False(maintenance.MatchString("main"))
+}
+
+func TestImportSemanticReleaseDefaultBranches(t *testing.T) {
+	assert := assert.New(t)
+
+	c, _, err := ImportSemanticRelease([]byte(`tagFormat: "${version}"`))
+	assert.NoError(err)
+	assert.Len(c.BumpStrategies, 8)
+	assert.Equal(BumpStrategy{BranchesPattern: `^alpha$`, Strategy: "AUTO", PreRelease: true, PreReleaseTemplate: "alpha"}, c.BumpStrategies[6])
+}
+
+func TestImportSemanticReleaseCommitAnalyzer(t *testing.T) {
+	assert := assert.New(t)
+
+	c, warnings, err := ImportSemanticRelease([]byte(`{
+  "name": "my-package",
+  "release": {
+    "branches": ["main"],
+    "repositoryUrl": "https://github.com/owner/repo.git",
+    "plugins": [
+      ["@semantic-release/commit-analyzer", {
+        "preset": "angular",
+        "releaseRules": [
+          {"type": "docs", "scope": "README", "release": "patch"},
+          {"type": "refactor", "release": "minor"},
+          {"scope": "api", "release": "major"},
+          {"scope": "no-release", "release": false},
+          {"type": "feat", "scope": "internal", "release": "patch"},
+          {"type": "style", "release": false},
+          {"breaking": true, "release": "major"},
+          {"subject": "*WIP*", "release": false},
+          {"type": "chore", "release": "prerelease"}
+        ],
+        "parserOpts": {"noteKeywords": ["BREAKING"]}
+      }],
+      "@semantic-release/release-notes-generator",
+      "@semantic-release/github"
+    ]
+  }
+}`))
+	assert.NoError(err)
+	assert.Equal(&Config{
+		Convention:   version.Angular,
+		MajorPattern: `(?:(?m)^BREAKING CHANGE:.+$)|(?:^[\w-]+\(api\)!?: .+)`,
+		MinorPattern: `(?:^feat(?:\(.+\))?: .+)|(?:^refactor(?:\(.+\))?!?: .+)`,
+		PatchPattern: `(?:^(?:fix|perf)(?:\(.+\))?: .+)|(?:^docs\(README\)!?: .+)`,
+		Scopes:       &Scopes{Ignore: []string{"no-release"}},
+		BumpStrategies: []BumpStrategy{
+			{BranchesPattern: `^main$`, Strategy: "AUTO"},
+			defaultBuildBumpStrategy(),
+		},
+	}, c)
+	assert.Equal(Warnings{
+		"ignored plugins, gsemver only computes the version: @semantic-release/release-notes-generator, @semantic-release/github",
+		"commit analyzer option parserOpts is ignored",
+		"release rule map[release:patch scope:internal type:feat] is ignored, it cannot lower the release of the angular convention",
+		"release rule map[release:false type:style] is ignored, only scopes can be excluded from the release",
+		"release rule map[release:false subject:*WIP*] is ignored, gsemver cannot match subject",
+		"release rule map[release:prerelease type:chore] is ignored, release prerelease is not supported",
+		"ignored options: repositoryUrl",
+	}, warnings)
+
+	for _, it := range []struct{ pattern, message string }{
+		{c.MajorPattern, "fix(api): remove endpoint"},
+		{c.MinorPattern, "refactor(core): simplify"},
+		{c.PatchPattern, "docs(README): typo"},
+	} {
+		assert.Regexp(it.pattern, it.message)
+	}
+	assert.NotRegexp(c.PatchPattern, "docs(guide): typo")
+}
+
+func TestImportSemanticReleaseUnknownPreset(t *testing.T) {
+	assert := assert.New(t)
+
+	c, warnings, err := ImportSemanticRelease([]byte(`{"preset": "ember", "branches": "main"}`))
+	assert.NoError(err)
+	assert.Equal(version.Angular, c.Convention)
+	assert.Equal(Warnings{`preset "ember" has no equivalent convention, angular is used instead`}, warnings)
+}
+
+func TestImportSemanticReleaseInvalid(t *testing.T) {
+	_, _, err := ImportSemanticRelease([]byte(`{"branches": [`))
+	assert.Error(t, err)
+}
@@ -1,12 +1,26 @@
 package git
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/arnaud-deprez/gsemver/internal/log"
 	"github.com/arnaud-deprez/gsemver/pkg/version"
 )
 
+const (
+	// BackendCLI is the git backend running the git binary
+	BackendCLI = "cli"
+	// BackendGo is the git backend reading the repository in go, without the git binary
+	BackendGo = "go"
+)
+
+// Backends returns the names of the git backends
+func Backends() []string {
+	return []string{BackendCLI, BackendGo}
+}
+
 // NewVersionGitRepo creates a version.GitRepo instance for a directory
 func NewVersionGitRepo(dir string) version.GitRepo {
 	return &gitRepoCLI{
@@ -15,6 +29,23 @@ func NewVersionGitRepo(dir string) version.GitRepo {
 	}
 }
 
+// NewVersionGitRepoWithBackend creates a version.GitRepo instance for a directory with a git backend.
+// The default backend is BackendCLI. The fetch options configure how a shallow clone is deepened.
+func NewVersionGitRepoWithBackend(backend string, dir string, fetch version.FetchOptions) (version.GitRepo, error) {
+	switch backend {
+	case "", BackendCLI:
+		return &gitRepoCLI{
+			dir:          dir,
+			commitParser: &commitParser{logFormat: logFormat},
+			fetch:        fetch,
+		}, nil
+	case BackendGo:
+		return &gitRepoGo{dir: dir, remote: fetch.Remote}, nil
+	default:
+		return nil, fmt.Errorf("unknown git backend %q, expected one of %s", backend, strings.Join(Backends(), ", "))
+	}
+}
+
 // NewDefaultVersionGitRepo creates a version.GitRepo instance with current working dir
 func NewDefaultVersionGitRepo() version.GitRepo {
 	dir, err := os.Getwd()
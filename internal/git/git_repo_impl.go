@@ -2,24 +2,33 @@ package git
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/arnaud-deprez/gsemver/internal/command"
 	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
 	"github.com/arnaud-deprez/gsemver/pkg/git"
 	"github.com/arnaud-deprez/gsemver/pkg/version"
 )
 
 const (
-	gitRepoBranchEnv = "GIT_BRANCH"
+	gitRemote = "origin"
+)
+
+var (
+	// shallowDeepenStep is the number of commits fetched the first time a shallow clone is deepened, it doubles each time
+	shallowDeepenStep = 50
+	// shallowDeepenMaxAttempts is the number of times a shallow clone is deepened to find a tag
+	shallowDeepenMaxAttempts = 8
 )
 
 type gitRepoCLI struct {
 	version.GitRepo
 	dir          string
 	commitParser *commitParser
+	// fetch configures how a shallow clone is deepened
+	fetch version.FetchOptions
 }
 
 // FetchTags implements version.GitRepo.FetchTags
@@ -32,6 +41,19 @@ func (g *gitRepoCLI) FetchTags() error {
 	return err
 }
 
+// FetchTagsFrom implements version.GitRepo.FetchTagsFrom
+func (g *gitRepoCLI) FetchTagsFrom(remote string, refspec string) error {
+	if remote == "" {
+		remote = gitRemote
+	}
+	args := []string{"fetch", "--tags", remote}
+	if refspec != "" {
+		args = append(args, refspec)
+	}
+	_, err := gitCmd(g).WithArgs(args...).Run()
+	return err
+}
+
 // GetCommits implements version.GitRepo.Getcommits
 func (g *gitRepoCLI) GetCommits(from string, to string) ([]git.Commit, error) {
 	rev := parseRev(from, to)
@@ -50,6 +72,25 @@ func (g *gitRepoCLI) GetCommits(from string, to string) ([]git.Commit, error) {
 	return g.commitParser.Parse(out), nil
 }
 
+// GetFirstParentCommits implements version.GitRepo.GetFirstParentCommits
+func (g *gitRepoCLI) GetFirstParentCommits(from string, to string) ([]git.Commit, error) {
+	rev := parseRev(from, to)
+	out, err := gitCmd(g).
+		WithArgs(
+			"log",
+			rev,
+			"--first-parent",
+			"--no-decorate",
+			"--pretty="+g.commitParser.logFormat,
+		).Run()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.commitParser.Parse(out), nil
+}
+
 // CountCommits implements version.GitRepo.CountCommits
 func (g *gitRepoCLI) CountCommits(from string, to string) (int, error) {
 	rev := parseRev(from, to)
@@ -65,14 +106,58 @@ func (g *gitRepoCLI) CountCommits(from string, to string) (int, error) {
 	return count, err
 }
 
-// GetLastRelativeTag - use git describe to retrieve the last relative tag
+// GetLastRelativeTag - use git describe to retrieve the last relative tag.
+// In a shallow clone, it deepens the history from the remote of the fetch options until a tag is found.
+// If the fetch is skipped, it does not deepen the history.
 func (g *gitRepoCLI) GetLastRelativeTag(rev string) (git.Tag, error) {
-	cmd := gitCmd(g).WithArgs("describe", "--tags", "--abbrev=0", "--match", "*[0-9]*.[0-9]*.[0-9]*", "--first-parent", rev)
-	out, err := cmd.Run()
+	depth := shallowDeepenStep
+	for attempt := 0; ; attempt++ {
+		cmd := gitCmd(g).WithArgs("describe", "--tags", "--abbrev=0", "--match", "*[0-9]*.[0-9]*.[0-9]*", "--first-parent", rev)
+		out, err := cmd.Run()
+		if err == nil {
+			return git.Tag{Name: strings.TrimSpace(out)}, nil
+		}
+		// without tag in the full history, the version starts from 0.0.0
+		if !g.isShallow() {
+			return git.Tag{}, err
+		}
+		if g.fetch.Skip {
+			return git.Tag{}, fmt.Errorf("%w: the fetch is skipped: %w", version.ErrShallowClone, err)
+		}
+		if attempt == shallowDeepenMaxAttempts {
+			return git.Tag{}, fmt.Errorf("%w: %d commits fetched: %w", version.ErrShallowClone, depth-shallowDeepenStep, err)
+		}
+		log.Info("GitRepo: no tag found in the shallow clone, fetch %d more commits", depth)
+		// even if the fetch errors are ignored, the local history has no tag to compute the version from
+		if _, err := gitCmd(g).WithArgs("fetch", "--deepen="+strconv.Itoa(depth), g.remote()).Run(); err != nil {
+			return git.Tag{}, fmt.Errorf("%w: cannot deepen it: %w", version.ErrShallowClone, err)
+		}
+		depth *= 2
+	}
+}
+
+// isShallow tells if the repository is a shallow clone
+func (g *gitRepoCLI) isShallow() bool {
+	out, err := gitCmd(g).WithArgs("rev-parse", "--is-shallow-repository").Run()
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// GetTags - use git tag to retrieve all the tags
+func (g *gitRepoCLI) GetTags() ([]git.Tag, error) {
+	out, err := gitCmd(g).WithArgs("tag", "--list").Run()
 	if err != nil {
-		return git.Tag{}, err
+		return nil, err
 	}
-	return git.Tag{Name: strings.TrimSpace(out)}, nil
+	return parseTags(out), nil
+}
+
+// GetTagsPointingAt - use git tag --points-at to retrieve the tags of a revision
+func (g *gitRepoCLI) GetTagsPointingAt(rev string) ([]git.Tag, error) {
+	out, err := gitCmd(g).WithArgs("tag", "--points-at", rev).Run()
+	if err != nil {
+		return nil, err
+	}
+	return parseTags(out), nil
 }
 
 // GetCurrentBranch - use git symbolic-ref to retrieve the current branch name
@@ -87,7 +172,7 @@ func (g *gitRepoCLI) GetCurrentBranch() (string, error) {
 		// And so we can retrieve the current branch name from environment variable.
 		branchFromEnv := getCurrentBranchFromEnv()
 		if branchFromEnv == "" {
-			return "", fmt.Errorf("unable to retrieve branch name from `git symbolic-ref HEAD` nor %s environment variable", gitRepoBranchEnv)
+			return "", fmt.Errorf("unable to retrieve branch name from `git symbolic-ref HEAD` nor %s or CI environment variables", ci.BranchEnv)
 		}
 		return branchFromEnv, nil
 	}
@@ -95,18 +180,83 @@ func (g *gitRepoCLI) GetCurrentBranch() (string, error) {
 	return branch, nil
 }
 
+// remote returns the remote of the fetch options, origin by default
+func (g *gitRepoCLI) remote() string {
+	if g.fetch.Remote != "" {
+		return g.fetch.Remote
+	}
+	return gitRemote
+}
+
+// GetRemoteURL - use git remote get-url to retrieve the URL of the remote of the fetch options, origin by default
+func (g *gitRepoCLI) GetRemoteURL() (string, error) {
+	out, err := gitCmd(g).WithArgs("remote", "get-url", g.remote()).Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// IsDirty - use git status to check if the tracked files have changes that are not committed
+func (g *gitRepoCLI) IsDirty() (bool, error) {
+	out, err := gitCmd(g).WithArgs("status", "--porcelain", "--untracked-files=no").Run()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// Commit - use git commit to commit the changes of the files only
+func (g *gitRepoCLI) Commit(message string, paths ...string) error {
+	_, err := gitCmd(g).WithArgs(append([]string{"commit", "--message", message, "--only", "--"}, paths...)...).Run()
+	return err
+}
+
+// CreateTag - use git tag to create an annotated tag on HEAD
+func (g *gitRepoCLI) CreateTag(name string, message string, sign bool) error {
+	mode := "--annotate"
+	if sign {
+		mode = "--sign"
+	}
+	// the message is kept verbatim as markdown headings would be stripped as comments otherwise
+	_, err := gitCmd(g).WithArgs("tag", mode, "--cleanup=verbatim", "--message", message, name).Run()
+	return err
+}
+
+// DeleteTag - use git tag --delete to delete a local tag
+func (g *gitRepoCLI) DeleteTag(name string) error {
+	_, err := gitCmd(g).WithArgs("tag", "--delete", name).Run()
+	return err
+}
+
+// PushTag - use git push --atomic to push a tag to the remote of the fetch options, origin by default
+func (g *gitRepoCLI) PushTag(name string) error {
+	ref := "refs/tags/" + name
+	out, err := gitCmd(g).WithArgs("push", "--atomic", "--porcelain", g.remote(), ref+":"+ref).Run()
+	if err != nil && strings.Contains(out, "[rejected]") {
+		return fmt.Errorf("%w: %w", version.ErrTagAlreadyExists, err)
+	}
+	return err
+}
+
 func getCurrentBranchFromEnv() string {
-	// We will use CI GIT_BRANCH environment variable.
-	// This need to be mapped with real environment variable from your CI server.
-	// TODO: eventually add support for most CI environment variable out of the box.
-	log.Trace("GitRepo: retrieve branch name from %s env variable", gitRepoBranchEnv)
-	return strings.TrimSpace(os.Getenv(gitRepoBranchEnv))
+	// GIT_BRANCH environment variable takes precedence over the ones of the CI provider.
+	log.Trace("GitRepo: retrieve branch name from %s or CI env variables", ci.BranchEnv)
+	return ci.CurrentBranch()
 }
 
 func gitCmd(g *gitRepoCLI) *command.Command {
 	return command.New("git").InDir(g.dir)
 }
 
+func parseTags(out string) []git.Tag {
+	tags := []git.Tag{}
+	for _, name := range strings.Fields(out) {
+		tags = append(tags, git.Tag{Name: name})
+	}
+	return tags
+}
+
 func parseRev(from string, to string) string {
 	if to == "" {
 		to = "HEAD"
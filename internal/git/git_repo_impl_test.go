@@ -0,0 +1,171 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+// newShallowClone clones with a depth of 5 a remote whose only tag is 30 commits before HEAD
+func newShallowClone(t *testing.T) (string, func(dir string, args ...string) string) {
+	dir := t.TempDir()
+	gitIn := func(dir string, args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(t, err, out)
+		return out
+	}
+	remote, work, clone := filepath.Join(dir, "remote.git"), filepath.Join(dir, "work"), filepath.Join(dir, "clone")
+	gitIn(dir, "init", "--bare", remote)
+	gitIn(dir, "clone", remote, work)
+	gitIn(work, "commit", "--allow-empty", "-m", "feat: initial feature")
+	gitIn(work, "tag", "-a", "v1.0.0", "-m", "Release 1.0.0")
+	for i := 0; i < 30; i++ {
+		gitIn(work, "commit", "--allow-empty", "-m", fmt.Sprintf("fix: fix %d", i))
+	}
+	gitIn(work, "push", "origin", "HEAD:refs/heads/main", "v1.0.0")
+	gitIn(dir, "clone", "--depth", "5", "--branch", "main", "file://"+remote, clone)
+	return clone, gitIn
+}
+
+func TestGetLastRelativeTagShallowClone(t *testing.T) {
+	assert := assert.New(t)
+
+	clone, gitIn := newShallowClone(t)
+	defer func(step int) { shallowDeepenStep = step }(shallowDeepenStep)
+	shallowDeepenStep = 4
+	gitRepo := NewVersionGitRepo(clone)
+	assert.NoError(gitRepo.FetchTags())
+
+	// 4, 8 then 16 more commits are fetched to reach the tag of the root commit
+	assert.Equal("5", gitIn(clone, "rev-list", "--count", "HEAD"))
+	tag, err := gitRepo.GetLastRelativeTag("HEAD")
+	assert.NoError(err)
+	assert.Equal("v1.0.0", tag.Name)
+	assert.Equal("31", gitIn(clone, "rev-list", "--count", "HEAD"))
+	assert.Equal("false", gitIn(clone, "rev-parse", "--is-shallow-repository"))
+
+	// without tag in the full history, it is not an error of the shallow clone
+	_, err = gitRepo.GetLastRelativeTag("v1.0.0^")
+	assert.Error(err)
+	assert.False(errors.Is(err, version.ErrShallowClone))
+}
+
+func TestGetLastRelativeTagShallowCloneLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	clone, _ := newShallowClone(t)
+	defer func(step, attempts int) { shallowDeepenStep, shallowDeepenMaxAttempts = step, attempts }(shallowDeepenStep, shallowDeepenMaxAttempts)
+	shallowDeepenStep, shallowDeepenMaxAttempts = 2, 2
+
+	_, err := NewVersionGitRepo(clone).GetLastRelativeTag("HEAD")
+	assert.ErrorIs(err, version.ErrShallowClone)
+	assert.ErrorContains(err, "no tag found in the shallow clone, fetch the full history with git fetch --unshallow: 6 commits fetched: ")
+
+	// the go backend cannot fetch more history
+	_, err = (&gitRepoGo{dir: clone}).GetLastRelativeTag("HEAD")
+	assert.ErrorIs(err, version.ErrShallowClone)
+	assert.ErrorContains(err, "the go git backend cannot fetch more history")
+}
+
+func TestGetLastRelativeTagShallowCloneFetchOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	clone, gitIn := newShallowClone(t)
+	defer func(step int) { shallowDeepenStep = step }(shallowDeepenStep)
+	shallowDeepenStep = 4
+
+	// without fetch, the shallow clone is not deepened
+	gitRepo, err := NewVersionGitRepoWithBackend(BackendCLI, clone, version.FetchOptions{Skip: true})
+	assert.NoError(err)
+	_, err = gitRepo.GetLastRelativeTag("HEAD")
+	assert.ErrorIs(err, version.ErrShallowClone)
+	assert.ErrorContains(err, "the fetch is skipped")
+	assert.Equal("5", gitIn(clone, "rev-list", "--count", "HEAD"))
+
+	// the shallow clone is deepened from the remote of the fetch options
+	gitIn(clone, "remote", "rename", "origin", "upstream")
+	gitRepo, err = NewVersionGitRepoWithBackend(BackendCLI, clone, version.FetchOptions{})
+	assert.NoError(err)
+	_, err = gitRepo.GetLastRelativeTag("HEAD")
+	assert.ErrorIs(err, version.ErrShallowClone)
+	assert.ErrorContains(err, "cannot deepen it")
+
+	gitRepo, err = NewVersionGitRepoWithBackend(BackendCLI, clone, version.FetchOptions{Remote: "upstream"})
+	assert.NoError(err)
+	assert.NoError(gitRepo.FetchTagsFrom("upstream", ""))
+	tag, err := gitRepo.GetLastRelativeTag("HEAD")
+	assert.NoError(err)
+	assert.Equal("v1.0.0", tag.Name)
+	assert.Equal("31", gitIn(clone, "rev-list", "--count", "HEAD"))
+}
+
+func TestFetchTagsFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	gitIn := func(dir string, args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err, out)
+		return out
+	}
+	remote, work := filepath.Join(dir, "remote.git"), filepath.Join(dir, "work")
+	gitIn(dir, "init", "--bare", remote)
+	gitIn(dir, "init", work)
+	gitIn(work, "commit", "--allow-empty", "-m", "feat: initial feature")
+	gitIn(work, "tag", "-a", "v1.0.0", "-m", "Release 1.0.0")
+	gitIn(work, "push", remote, "HEAD:refs/heads/main", "v1.0.0")
+	gitIn(work, "tag", "-d", "v1.0.0")
+
+	// without origin, the default remote cannot be fetched
+	gitRepo := NewVersionGitRepo(work)
+	assert.Error(gitRepo.FetchTagsFrom("", ""))
+
+	gitIn(work, "remote", "add", "upstream", remote)
+	assert.NoError(gitRepo.FetchTagsFrom("upstream", "+refs/heads/main:refs/remotes/upstream/main"))
+	assert.Equal("v1.0.0", gitIn(work, "tag", "--list"))
+	assert.Equal(gitIn(work, "rev-parse", "HEAD"), gitIn(work, "rev-parse", "upstream/main"))
+
+	// the go backend does not fetch
+	assert.NoError((&gitRepoGo{dir: work}).FetchTagsFrom("upstream", ""))
+}
+
+func TestPushTagToFetchRemote(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	gitIn := func(dir string, args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err, out)
+		return out
+	}
+	remote, work := filepath.Join(dir, "remote.git"), filepath.Join(dir, "work")
+	gitIn(dir, "init", "--bare", remote)
+	gitIn(dir, "init", work)
+	gitIn(work, "commit", "--allow-empty", "-m", "feat: initial feature")
+	gitIn(work, "tag", "-a", "v1.0.0", "-m", "Release 1.0.0")
+	gitIn(work, "remote", "add", "upstream", remote)
+
+	// without origin, the remote of the fetch options is used
+	gitRepo, err := NewVersionGitRepoWithBackend(BackendCLI, work, version.FetchOptions{Remote: "upstream"})
+	assert.NoError(err)
+	url, err := gitRepo.GetRemoteURL()
+	assert.NoError(err)
+	assert.Equal(remote, url)
+	assert.NoError(gitRepo.PushTag("v1.0.0"))
+	assert.Contains(gitIn(work, "ls-remote", "--tags", "upstream"), "refs/tags/v1.0.0")
+
+	goRepo, err := NewVersionGitRepoWithBackend(BackendGo, work, version.FetchOptions{Remote: "upstream"})
+	assert.NoError(err)
+	url, err = goRepo.GetRemoteURL()
+	assert.NoError(err)
+	assert.Equal(remote, url)
+
+	_, err = NewVersionGitRepo(work).GetRemoteURL()
+	assert.Error(err)
+}
@@ -0,0 +1,545 @@
+package git
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// slop is the number of commits walked once only uninteresting commits are left, like git does for clock skews
+	slop = 5
+)
+
+var (
+	// describeMatchRegex is the equivalent of the *[0-9]*.[0-9]*.[0-9]* pattern of git describe --match
+	describeMatchRegex = regexp.MustCompile(`^.*[0-9].*\..*[0-9].*\..*[0-9].*$`)
+	// revSuffixRegex matches the ^, ^N, ~ and ~N suffixes of a revision
+	revSuffixRegex = regexp.MustCompile(`^(\^\{\w*\}|[\^~][0-9]*)`)
+)
+
+// commitNode is a parsed commit with the flags of the walks
+type commitNode struct {
+	id        objectID
+	parents   []*commitNode
+	author    git.Signature
+	committer git.Signature
+	message   string
+	parsed    bool
+
+	seen          bool
+	uninteresting bool
+}
+
+func (c *commitNode) toCommit() git.Commit {
+	return git.Commit{
+		Hash:      git.Hash(c.id.String()),
+		Author:    c.author,
+		Committer: c.committer,
+		Message:   c.message,
+	}
+}
+
+// commitQueue is a priority queue of commits sorted by committer date, the most recent first.
+// The commits with the same date are sorted by insertion order like the walks of git.
+type commitQueue struct {
+	items []queueItem
+	seq   int
+}
+
+type queueItem struct {
+	commit *commitNode
+	seq    int
+}
+
+func (q *commitQueue) Len() int { return len(q.items) }
+func (q *commitQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if !a.commit.committer.When.Equal(b.commit.committer.When) {
+		return a.commit.committer.When.After(b.commit.committer.When)
+	}
+	return a.seq < b.seq
+}
+func (q *commitQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *commitQueue) Push(x interface{}) {
+	q.items = append(q.items, queueItem{commit: x.(*commitNode), seq: q.seq})
+	q.seq++
+}
+func (q *commitQueue) Pop() interface{} {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item.commit
+}
+
+func (q *commitQueue) put(c *commitNode) { heap.Push(q, c) }
+func (q *commitQueue) get() *commitNode  { return heap.Pop(q).(*commitNode) }
+
+// everybodyUninteresting tells if only uninteresting commits are left
+func (q *commitQueue) everybodyUninteresting() bool {
+	for _, it := range q.items {
+		if !it.commit.uninteresting {
+			return false
+		}
+	}
+	return true
+}
+
+// newestDate returns the date of the most recent commit of the queue
+func (q *commitQueue) newestDate() time.Time {
+	return q.items[0].commit.committer.When
+}
+
+// commit returns the parsed commit of an id
+func (r *goRepository) commit(id objectID) (*commitNode, error) {
+	c := r.node(id)
+	if c.parsed {
+		return c, nil
+	}
+	obj, err := r.objects.read(id)
+	if err != nil {
+		return nil, err
+	}
+	if obj.typ != objCommit {
+		return nil, fmt.Errorf("object %s is a %s, not a commit", id, objTypeNames[obj.typ])
+	}
+	header, message, _ := strings.Cut(string(obj.data), "\n\n")
+	c.message = strings.TrimSpace(message)
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "parent":
+			pid, ok := parseObjectID(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid parent %s of commit %s", value, id)
+			}
+			c.parents = append(c.parents, r.node(pid))
+		case "author":
+			c.author = parseSignature(value)
+		case "committer":
+			c.committer = parseSignature(value)
+		}
+	}
+	// the parents of the commits at the boundary of a shallow clone are missing
+	if r.shallow[id] {
+		c.parents = nil
+	}
+	c.parsed = true
+	return c, nil
+}
+
+// node returns the unique node of a commit id, parsed or not
+func (r *goRepository) node(id objectID) *commitNode {
+	c, ok := r.commits[id]
+	if !ok {
+		c = &commitNode{id: id}
+		r.commits[id] = c
+	}
+	return c
+}
+
+// resetWalk clears the flags of the previous walk
+func (r *goRepository) resetWalk() {
+	for _, c := range r.commits {
+		c.seen = false
+		c.uninteresting = false
+	}
+}
+
+// walk lists the commits reachable from to but not from from, sorted like git log.
+// If firstParent is true, only the first parent of the reachable commits is followed.
+func (r *goRepository) walk(from, to string, firstParent bool) ([]*commitNode, error) {
+	r.resetWalk()
+	var tips []*commitNode
+	if from != "" {
+		c, err := r.revCommit(from)
+		if err != nil {
+			return nil, err
+		}
+		c.uninteresting = true
+		tips = append(tips, c)
+	}
+	c, err := r.revCommit(to)
+	if err != nil {
+		return nil, err
+	}
+	tips = append(tips, c)
+
+	queue := &commitQueue{}
+	for _, c := range tips {
+		if !c.seen {
+			c.seen = true
+			queue.put(c)
+		}
+	}
+
+	var ret []*commitNode
+	lastDate := time.Unix(1<<62, 0)
+	remaining := slop
+	for queue.Len() > 0 {
+		c := queue.get()
+		if c.uninteresting {
+			for _, p := range c.parents {
+				if err := r.markUninteresting(p); err != nil {
+					return nil, err
+				}
+				if !p.seen {
+					p.seen = true
+					queue.put(p)
+				}
+			}
+			// stop once only uninteresting commits older than the last interesting one are left
+			if queue.Len() == 0 {
+				break
+			}
+			if !queue.newestDate().Before(lastDate) || !queue.everybodyUninteresting() {
+				remaining = slop
+				continue
+			}
+			if remaining--; remaining == 0 {
+				break
+			}
+			continue
+		}
+
+		for i, p := range c.parents {
+			if firstParent && i > 0 {
+				break
+			}
+			if _, err := r.commit(p.id); err != nil {
+				return nil, err
+			}
+			if !p.seen {
+				p.seen = true
+				queue.put(p)
+			}
+		}
+		lastDate = c.committer.When
+		ret = append(ret, c)
+	}
+
+	commits := ret[:0]
+	for _, c := range ret {
+		if !c.uninteresting {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
+
+// markUninteresting marks a commit and its ancestors already parsed as uninteresting
+func (r *goRepository) markUninteresting(c *commitNode) error {
+	if _, err := r.commit(c.id); err != nil {
+		return err
+	}
+	stack := []*commitNode{c}
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if it.uninteresting && it != c {
+			continue
+		}
+		it.uninteresting = true
+		stack = append(stack, it.parents...)
+	}
+	return nil
+}
+
+// countAncestryPath counts the commits of the walk from..to that are descendants of from like git rev-list --ancestry-path
+func (r *goRepository) countAncestryPath(from, to string) (int, error) {
+	if from == "" {
+		return 0, fmt.Errorf("--ancestry-path given but there are no bottom commits")
+	}
+	commits, err := r.walk(from, to, false)
+	if err != nil {
+		return 0, err
+	}
+	bottom, err := r.revCommit(from)
+	if err != nil {
+		return 0, err
+	}
+	onPath := map[*commitNode]bool{bottom: true}
+	for changed := true; changed; {
+		changed = false
+		// the parents are mostly after their children
+		for i := len(commits) - 1; i >= 0; i-- {
+			c := commits[i]
+			if onPath[c] {
+				continue
+			}
+			for _, p := range c.parents {
+				if onPath[p] {
+					onPath[c] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return len(onPath) - 1, nil
+}
+
+// describeTag is a tag that can describe a commit
+type describeTag struct {
+	name      string
+	annotated bool
+	date      time.Time
+}
+
+// describe returns the name of the closest tag matching describeMatchRegex following the first parents
+// like git describe --tags --abbrev=0 --first-parent
+func (r *goRepository) describe(rev string) (string, error) {
+	c, err := r.revCommit(rev)
+	if err != nil {
+		return "", err
+	}
+
+	refs, err := r.refs.list("refs/tags/")
+	if err != nil {
+		return "", err
+	}
+	names := map[objectID]describeTag{}
+	for _, it := range refs {
+		name := strings.TrimPrefix(it.name, "refs/tags/")
+		if !describeMatchRegex.MatchString(name) {
+			continue
+		}
+		target, tag, err := r.peel(it)
+		if err != nil {
+			continue
+		}
+		candidate := describeTag{name: name, annotated: tag != nil}
+		if tag != nil {
+			candidate.date = tag.date
+		}
+		// prefer the annotated tags, then the most recent annotated tag, then the first one by name
+		e, exists := names[target]
+		if !exists || (!e.annotated && candidate.annotated) || (e.annotated && candidate.annotated && e.date.Before(candidate.date)) {
+			names[target] = candidate
+		}
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no names found, cannot describe %s", rev)
+	}
+
+	for {
+		if tag, ok := names[c.id]; ok {
+			return tag.name, nil
+		}
+		if len(c.parents) == 0 {
+			return "", fmt.Errorf("no tags can describe %s", rev)
+		}
+		if c, err = r.commit(c.parents[0].id); err != nil {
+			return "", err
+		}
+	}
+}
+
+// tagObject is a parsed annotated tag
+type tagObject struct {
+	object objectID
+	date   time.Time
+}
+
+// readTag reads an annotated tag
+func (r *goRepository) readTag(data []byte) (*tagObject, error) {
+	header, _, _ := bytes.Cut(data, []byte("\n\n"))
+	tag := &tagObject{}
+	found := false
+	for _, line := range strings.Split(string(header), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "object":
+			tag.object, found = parseObjectID(value)
+		case "tagger":
+			tag.date = parseSignature(value).When
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid tag object")
+	}
+	return tag, nil
+}
+
+// peel returns the commit a reference points to and its first annotated tag, if any
+func (r *goRepository) peel(it ref) (objectID, *tagObject, error) {
+	var first *tagObject
+	id := it.id
+	for depth := 0; depth < 10; depth++ {
+		obj, err := r.objects.read(id)
+		if err != nil {
+			return id, nil, err
+		}
+		if obj.typ != objTag {
+			if obj.typ != objCommit {
+				return id, nil, fmt.Errorf("%s does not point to a commit", it.name)
+			}
+			return id, first, nil
+		}
+		tag, err := r.readTag(obj.data)
+		if err != nil {
+			return id, nil, err
+		}
+		if first == nil {
+			first = tag
+		}
+		id = tag.object
+	}
+	return id, nil, fmt.Errorf("too many nested tags for %s", it.name)
+}
+
+// peelAll returns the object of a reference and the objects of its nested tags
+func (r *goRepository) peelAll(id objectID) []objectID {
+	ret := []objectID{id}
+	for depth := 0; depth < 10; depth++ {
+		obj, err := r.objects.read(id)
+		if err != nil || obj.typ != objTag {
+			return ret
+		}
+		tag, err := r.readTag(obj.data)
+		if err != nil {
+			return ret
+		}
+		id = tag.object
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+// revObject resolves a revision such as HEAD, a tag, a branch, a hash or an abbreviated hash
+// followed by ^, ^N, ~N or ^{} suffixes
+func (r *goRepository) revObject(rev string) (objectID, error) {
+	name := rev
+	suffixes := ""
+	if i := strings.IndexAny(rev, "^~"); i >= 0 {
+		name, suffixes = rev[:i], rev[i:]
+	}
+	id, err := r.resolveName(name)
+	if err != nil {
+		return id, err
+	}
+
+	for suffixes != "" {
+		m := revSuffixRegex.FindString(suffixes)
+		if m == "" {
+			return id, fmt.Errorf("unknown revision %s", rev)
+		}
+		suffixes = suffixes[len(m):]
+		if strings.HasPrefix(m, "^{") {
+			if m != "^{}" && m != "^{commit}" {
+				return id, fmt.Errorf("unsupported revision %s", rev)
+			}
+			if id, _, err = r.peel(ref{name: rev, id: id}); err != nil {
+				return id, err
+			}
+			continue
+		}
+		n := 1
+		if len(m) > 1 {
+			n, _ = strconv.Atoi(m[1:])
+		}
+		if id, _, err = r.peel(ref{name: rev, id: id}); err != nil {
+			return id, err
+		}
+		c, err := r.commit(id)
+		if err != nil {
+			return id, err
+		}
+		if m[0] == '^' {
+			// ^0 is the commit itself
+			if n == 0 {
+				continue
+			}
+			if n > len(c.parents) {
+				return id, fmt.Errorf("unknown revision %s", rev)
+			}
+			id = c.parents[n-1].id
+			continue
+		}
+		for i := 0; i < n; i++ {
+			if len(c.parents) == 0 {
+				return id, fmt.Errorf("unknown revision %s", rev)
+			}
+			if c, err = r.commit(c.parents[0].id); err != nil {
+				return id, err
+			}
+		}
+		id = c.id
+	}
+	return id, nil
+}
+
+// revCommit resolves a revision to a parsed commit
+func (r *goRepository) revCommit(rev string) (*commitNode, error) {
+	id, err := r.revObject(rev)
+	if err != nil {
+		return nil, err
+	}
+	if id, _, err = r.peel(ref{name: rev, id: id}); err != nil {
+		return nil, err
+	}
+	return r.commit(id)
+}
+
+// resolveName resolves a name without suffix in the same order as git
+func (r *goRepository) resolveName(name string) (objectID, error) {
+	if id, ok := parseObjectID(name); ok {
+		return id, nil
+	}
+	candidates := []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name, "refs/remotes/" + name + "/HEAD"}
+	if name == "" || name == "@" {
+		candidates = []string{"HEAD"}
+	}
+	for _, it := range candidates {
+		// only HEAD and the special references are outside refs/
+		if it == name && !strings.HasPrefix(name, "refs/") && strings.ToUpper(name) != name {
+			continue
+		}
+		if id, err := r.refs.resolve(it); err == nil {
+			return id, nil
+		}
+	}
+	if len(name) >= 4 && len(name) < 2*hashSize && isHex(name) {
+		ids := r.objects.findPrefix(strings.ToLower(name))
+		if len(ids) == 1 {
+			return ids[0], nil
+		}
+		if len(ids) > 1 {
+			return objectID{}, fmt.Errorf("short object ID %s is ambiguous", name)
+		}
+	}
+	return objectID{}, fmt.Errorf("unknown revision %s", name)
+}
+
+func isHex(s string) bool {
+	for _, ch := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSignature parses the identity and date of an author, committer or tagger: Name <email> timestamp timezone
+func parseSignature(value string) git.Signature {
+	ret := git.Signature{}
+	start, end := strings.Index(value, "<"), strings.LastIndex(value, ">")
+	if start < 0 || end < start {
+		return ret
+	}
+	ret.Name = strings.TrimSpace(value[:start])
+	ret.Email = value[start+1 : end]
+	fields := strings.Fields(value[end+1:])
+	if len(fields) > 0 {
+		ts, _ := strconv.ParseInt(fields[0], 10, 64)
+		ret.When = time.Unix(ts, 0)
+	} else {
+		ret.When = time.Unix(0, 0)
+	}
+	return ret
+}
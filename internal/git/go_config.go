@@ -0,0 +1,154 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitConfig is the content of git configuration files.
+// The keys are in the form section.subsection.name where the section and the name are lower case.
+type gitConfig map[string][]string
+
+// get returns the last value of a key
+func (c gitConfig) get(key string) string {
+	values := c[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// readGitConfig reads the system, global and repository configuration files, the last ones override the first ones
+func readGitConfig(commonDir string) gitConfig {
+	var paths []string
+	if os.Getenv("GIT_CONFIG_NOSYSTEM") == "" {
+		paths = append(paths, "/etc/gitconfig")
+	}
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		paths = append(paths, global)
+	} else {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		home, _ := os.UserHomeDir()
+		if xdg == "" && home != "" {
+			xdg = filepath.Join(home, ".config")
+		}
+		if xdg != "" {
+			paths = append(paths, filepath.Join(xdg, "git", "config"))
+		}
+		if home != "" {
+			paths = append(paths, filepath.Join(home, ".gitconfig"))
+		}
+	}
+	if commonDir != "" {
+		paths = append(paths, filepath.Join(commonDir, "config"))
+	}
+
+	c := gitConfig{}
+	for _, path := range paths {
+		if data, err := os.ReadFile(path); err == nil {
+			c.parse(string(data))
+		}
+	}
+	return c
+}
+
+// parse parses the content of a configuration file. The includes are not supported.
+func (c gitConfig) parse(content string) {
+	section := ""
+	for _, line := range joinContinuationLines(content) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			end := strings.Index(line, "]")
+			if end < 0 {
+				continue
+			}
+			section = parseConfigSection(line[1:end])
+			line = strings.TrimSpace(line[end+1:])
+		}
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		name, value, found := strings.Cut(line, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found {
+			// a name without value is a true boolean
+			value = "true"
+			name = strings.ToLower(strings.Fields(name)[0])
+		} else {
+			value = parseConfigValue(value)
+		}
+		key := section + "." + name
+		c[key] = append(c[key], value)
+	}
+}
+
+// parseConfigSection parses [section "subsection"] or the deprecated [section.subsection]
+func parseConfigSection(header string) string {
+	name, sub, found := strings.Cut(header, " ")
+	if !found {
+		if name, sub, found = strings.Cut(header, "."); found {
+			return strings.ToLower(name) + "." + strings.ToLower(sub)
+		}
+		return strings.ToLower(header)
+	}
+	sub = strings.TrimSpace(sub)
+	sub = strings.TrimSuffix(strings.TrimPrefix(sub, `"`), `"`)
+	sub = strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(sub)
+	return strings.ToLower(name) + "." + sub
+}
+
+// parseConfigValue removes the quotes, the escapes and the comments of a value
+func parseConfigValue(value string) string {
+	var sb strings.Builder
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case ch == '"':
+			quoted = !quoted
+		case ch == '\\' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b':
+				sb.WriteByte('\b')
+			default:
+				sb.WriteByte(value[i])
+			}
+		case (ch == '#' || ch == ';') && !quoted:
+			return strings.TrimSpace(sb.String())
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// joinContinuationLines splits the content in lines, the lines ending with a backslash continue on the next line
+func joinContinuationLines(content string) []string {
+	var lines []string
+	current := ""
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+			current += strings.TrimSuffix(line, `\`)
+			continue
+		}
+		lines = append(lines, current+line)
+		current = ""
+	}
+	return append(lines, current)
+}
+
+// isTrue tells if the value of a configuration key is a true boolean
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
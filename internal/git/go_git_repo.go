@@ -0,0 +1,429 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+// goRepository is a git repository read without the git binary
+type goRepository struct {
+	gitDir    string
+	commonDir string
+	// workTree is the directory of the working tree, empty for a bare repository
+	workTree string
+	config   gitConfig
+	objects  *objectStore
+	refs     *refStore
+	shallow  map[objectID]bool
+	commits  map[objectID]*commitNode
+}
+
+// openGoRepository opens the repository of a directory or of one of its parents like git does
+func openGoRepository(dir string) (*goRepository, error) {
+	gitDir, workTree, err := findGitDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	r := &goRepository{
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		workTree:  workTree,
+		config:    readGitConfig(commonDir),
+		refs:      &refStore{gitDir: gitDir, commonDir: commonDir},
+		shallow:   map[objectID]bool{},
+		commits:   map[objectID]*commitNode{},
+	}
+	if format := r.config.get("extensions.objectformat"); format != "" && format != "sha1" {
+		return nil, fmt.Errorf("the %s object format of %s is not supported by the go git backend", format, gitDir)
+	}
+	if storage := r.config.get("extensions.refstorage"); storage != "" && storage != "files" {
+		return nil, fmt.Errorf("the %s reference storage of %s is not supported by the go git backend", storage, gitDir)
+	}
+	if isTrue(r.config.get("core.bare")) {
+		r.workTree = ""
+	} else if path := r.config.get("core.worktree"); path != "" && os.Getenv("GIT_WORK_TREE") == "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(gitDir, path)
+		}
+		r.workTree = path
+	}
+	if r.objects, err = newObjectStore(filepath.Join(commonDir, "objects")); err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(filepath.Join(commonDir, "shallow")); err == nil {
+		for _, line := range strings.Fields(string(data)) {
+			if id, ok := parseObjectID(line); ok {
+				r.shallow[id] = true
+			}
+		}
+	}
+	return r, nil
+}
+
+// findGitDir finds the git directory and the working tree from GIT_DIR or from the directory and its parents
+func findGitDir(dir string) (string, string, error) {
+	if gitDir := os.Getenv("GIT_DIR"); gitDir != "" {
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+		// without GIT_WORK_TREE, the current directory is the top of the working tree like git does
+		workTree := os.Getenv("GIT_WORK_TREE")
+		if workTree == "" {
+			workTree = dir
+		}
+		return gitDir, workTree, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		dotGit := filepath.Join(current, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil && info.IsDir() {
+			return dotGit, current, nil
+		}
+		if err == nil {
+			// the .git file of a worktree or a submodule points to its git directory
+			data, err := os.ReadFile(dotGit)
+			if err != nil {
+				return "", "", err
+			}
+			gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return "", "", fmt.Errorf("invalid gitdir file %s", dotGit)
+			}
+			gitDir = strings.TrimSpace(gitDir)
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(current, gitDir)
+			}
+			return gitDir, current, nil
+		}
+		if isBareRepository(current) {
+			return current, "", nil
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("not a git repository (or any of the parent directories): %s", dir)
+		}
+	}
+}
+
+func isBareRepository(dir string) bool {
+	for _, it := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, it)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// gitRepoGo implements version.GitRepo by reading the repository directly, without the git binary.
+// It cannot fetch, commit nor push.
+type gitRepoGo struct {
+	dir  string
+	repo *goRepository
+	// remote is the remote of the fetch options, origin if empty
+	remote string
+}
+
+// open opens the repository the first time it is used
+func (g *gitRepoGo) open() (*goRepository, error) {
+	if g.repo == nil {
+		repo, err := openGoRepository(g.dir)
+		if err != nil {
+			return nil, err
+		}
+		g.repo = repo
+	}
+	return g.repo, nil
+}
+
+// FetchTags implements version.GitRepo.FetchTags.
+// The go backend cannot fetch, the tags must have been fetched by the checkout.
+func (g *gitRepoGo) FetchTags() error {
+	return g.FetchTagsFrom("", "")
+}
+
+// FetchTagsFrom implements version.GitRepo.FetchTagsFrom like FetchTags, nothing is fetched
+func (g *gitRepoGo) FetchTagsFrom(remote string, _ string) error {
+	r, err := g.open()
+	if err != nil {
+		return err
+	}
+	if remote == "" {
+		remote = gitRemote
+	}
+	if url := r.config.get("remote." + remote + ".url"); url != "" {
+		log.Warn("GitRepo: the go git backend cannot fetch the tags of %s, only the local tags are used", url)
+	}
+	return nil
+}
+
+// GetCommits implements version.GitRepo.GetCommits
+func (g *gitRepoGo) GetCommits(from string, to string) ([]git.Commit, error) {
+	return g.commits(from, to, false)
+}
+
+// GetFirstParentCommits implements version.GitRepo.GetFirstParentCommits
+func (g *gitRepoGo) GetFirstParentCommits(from string, to string) ([]git.Commit, error) {
+	return g.commits(from, to, true)
+}
+
+func (g *gitRepoGo) commits(from string, to string, firstParent bool) ([]git.Commit, error) {
+	r, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	nodes, err := r.walk(from, to, firstParent)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]git.Commit, len(nodes))
+	for i, c := range nodes {
+		commits[i] = c.toCommit()
+	}
+	return commits, nil
+}
+
+// CountCommits implements version.GitRepo.CountCommits
+func (g *gitRepoGo) CountCommits(from string, to string) (int, error) {
+	r, err := g.open()
+	if err != nil {
+		return -1, err
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	count, err := r.countAncestryPath(from, to)
+	if err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+// GetLastRelativeTag implements version.GitRepo.GetLastRelativeTag
+func (g *gitRepoGo) GetLastRelativeTag(rev string) (git.Tag, error) {
+	r, err := g.open()
+	if err != nil {
+		return git.Tag{}, err
+	}
+	name, err := r.describe(rev)
+	if err != nil && len(r.shallow) > 0 {
+		return git.Tag{}, fmt.Errorf("%w: the go git backend cannot fetch more history: %w", version.ErrShallowClone, err)
+	}
+	if err != nil {
+		return git.Tag{}, err
+	}
+	return git.Tag{Name: name}, nil
+}
+
+// GetTags implements version.GitRepo.GetTags
+func (g *gitRepoGo) GetTags() ([]git.Tag, error) {
+	r, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.refs.list("refs/tags/")
+	if err != nil {
+		return nil, err
+	}
+	tags := []git.Tag{}
+	for _, it := range refs {
+		tags = append(tags, git.Tag{Name: strings.TrimPrefix(it.name, "refs/tags/")})
+	}
+	return tags, nil
+}
+
+// GetTagsPointingAt implements version.GitRepo.GetTagsPointingAt
+func (g *gitRepoGo) GetTagsPointingAt(rev string) ([]git.Tag, error) {
+	r, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.revObject(rev)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.refs.list("refs/tags/")
+	if err != nil {
+		return nil, err
+	}
+	tags := []git.Tag{}
+	for _, it := range refs {
+		// the tag itself or one of the objects it peels to
+		for _, target := range r.peelAll(it.id) {
+			if target == id {
+				tags = append(tags, git.Tag{Name: strings.TrimPrefix(it.name, "refs/tags/")})
+				break
+			}
+		}
+	}
+	return tags, nil
+}
+
+// GetCurrentBranch implements version.GitRepo.GetCurrentBranch
+func (g *gitRepoGo) GetCurrentBranch() (string, error) {
+	r, err := g.open()
+	if err != nil {
+		return "", err
+	}
+	target, err := r.refs.symbolic("HEAD")
+	if err != nil || target == "" {
+		// detached HEAD like during most of CI builds
+		branchFromEnv := getCurrentBranchFromEnv()
+		if branchFromEnv == "" {
+			return "", fmt.Errorf("unable to retrieve branch name from HEAD nor %s or CI environment variables", ci.BranchEnv)
+		}
+		return branchFromEnv, nil
+	}
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/", "refs/"} {
+		if name, ok := strings.CutPrefix(target, prefix); ok {
+			return name, nil
+		}
+	}
+	return target, nil
+}
+
+// GetRemoteURL implements version.GitRepo.GetRemoteURL with the remote of the fetch options, origin by default
+func (g *gitRepoGo) GetRemoteURL() (string, error) {
+	r, err := g.open()
+	if err != nil {
+		return "", err
+	}
+	remote := g.remote
+	if remote == "" {
+		remote = gitRemote
+	}
+	url := r.config.get("remote." + remote + ".url")
+	if url == "" {
+		return "", fmt.Errorf("no such remote '%s'", remote)
+	}
+	// the longest insteadOf prefix rewrites the URL like git does
+	base, prefix := "", ""
+	for key, values := range r.config {
+		b, ok := strings.CutPrefix(key, "url.")
+		if !ok || !strings.HasSuffix(b, ".insteadof") {
+			continue
+		}
+		for _, it := range values {
+			if strings.HasPrefix(url, it) && len(it) > len(prefix) {
+				base, prefix = strings.TrimSuffix(b, ".insteadof"), it
+			}
+		}
+	}
+	if prefix != "" {
+		url = base + strings.TrimPrefix(url, prefix)
+	}
+	return url, nil
+}
+
+// IsDirty implements version.GitRepo.IsDirty by comparing HEAD, the index and the working tree like git status does
+func (g *gitRepoGo) IsDirty() (bool, error) {
+	r, err := g.open()
+	if err != nil {
+		return false, err
+	}
+	return r.isDirty()
+}
+
+// Commit implements version.GitRepo.Commit
+func (g *gitRepoGo) Commit(_ string, _ ...string) error {
+	return errUnsupported("commit")
+}
+
+// CreateTag implements version.GitRepo.CreateTag by writing the tag object and its reference
+func (g *gitRepoGo) CreateTag(name string, message string, sign bool) error {
+	if sign {
+		return errUnsupported("signing tags")
+	}
+	r, err := g.open()
+	if err != nil {
+		return err
+	}
+	head, err := r.revCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	tagger, err := r.committerIdentity()
+	if err != nil {
+		return err
+	}
+	if r.refs.exists("refs/tags/" + name) {
+		return fmt.Errorf("tag '%s' already exists", name)
+	}
+	// the message is kept verbatim like the cli backend does
+	content := fmt.Sprintf("object %s\ntype commit\ntag %s\ntagger %s\n\n%s", head.id, name, tagger, message)
+	id, err := r.objects.write(objTag, []byte(content))
+	if err != nil {
+		return fmt.Errorf("cannot write tag %s: %v", name, err)
+	}
+	return r.refs.create("refs/tags/"+name, id)
+}
+
+// DeleteTag implements version.GitRepo.DeleteTag
+func (g *gitRepoGo) DeleteTag(name string) error {
+	r, err := g.open()
+	if err != nil {
+		return err
+	}
+	if err := r.refs.delete("refs/tags/" + name); err != nil {
+		if errors.Is(err, errRefNotFound) {
+			return fmt.Errorf("tag '%s' not found", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// PushTag implements version.GitRepo.PushTag
+func (g *gitRepoGo) PushTag(_ string) error {
+	return errUnsupported("pushing tags")
+}
+
+// committerIdentity returns the identity and the current date of the committer in the format of git objects
+func (r *goRepository) committerIdentity() (string, error) {
+	name, email := os.Getenv("GIT_COMMITTER_NAME"), os.Getenv("GIT_COMMITTER_EMAIL")
+	if name == "" {
+		name = r.config.get("user.name")
+	}
+	if email == "" {
+		email = r.config.get("user.email")
+	}
+	if name == "" || email == "" {
+		return "", errors.New("unable to find the identity of the tagger, set user.name and user.email in the git configuration")
+	}
+
+	date := os.Getenv("GIT_COMMITTER_DATE")
+	if date == "" {
+		now := time.Now()
+		date = strconv.FormatInt(now.Unix(), 10) + " " + now.Format("-0700")
+	}
+	// only the raw format of git is supported: @<timestamp> <timezone>
+	date = strings.TrimPrefix(date, "@")
+	return fmt.Sprintf("%s <%s> %s", name, email, date), nil
+}
+
+func errUnsupported(operation string) error {
+	return fmt.Errorf("%s is not supported by the go git backend, use the cli git backend", operation)
+}
@@ -0,0 +1,243 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+// newTestRepository creates a repository with merges, commits of the same second and different kinds of tags
+func newTestRepository(t *testing.T) (string, func(args ...string) string) {
+	dir := t.TempDir()
+	date := 1700000000
+	gitIn := func(args ...string) string {
+		// several commits share the same date to check the order of the commits of the same second
+		d := fmt.Sprintf("%d +0100", date/2)
+		date++
+		out, err := command.New("git").InDir(dir).WithEnv(map[string]string{"GIT_COMMITTER_DATE": d, "GIT_AUTHOR_DATE": d}).
+			WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(t, err, out)
+		return out
+	}
+	gitIn("init", "--initial-branch", "main")
+	gitIn("commit", "--allow-empty", "-m", "feat: initial feature")
+	gitIn("tag", "-a", "v0.1.0", "-m", "Release 0.1.0")
+	gitIn("commit", "--allow-empty", "-m", "fix: first fix\n\nwith a body")
+	gitIn("checkout", "-b", "feature/one")
+	gitIn("commit", "--allow-empty", "-m", "feat: feature one")
+	gitIn("commit", "--allow-empty", "-m", "fix: feature one fix")
+	gitIn("checkout", "main")
+	gitIn("commit", "--allow-empty", "-m", "fix: main fix")
+	gitIn("tag", "v0.1.1")
+	gitIn("tag", "-a", "tools/v0.1.1", "-m", "Release tools 0.1.1")
+	gitIn("merge", "--no-ff", "-m", "Merge feature/one", "feature/one")
+	gitIn("checkout", "-b", "release/0.2", "HEAD~1")
+	gitIn("commit", "--allow-empty", "-m", "fix: release fix")
+	gitIn("tag", "-a", "v0.1.2", "-m", "Release 0.1.2")
+	gitIn("checkout", "main")
+	gitIn("merge", "--no-ff", "-m", "Merge release/0.2", "release/0.2")
+	gitIn("tag", "-a", "v0.2.0", "-m", "Release 0.2.0")
+	gitIn("tag", "not-a-version")
+	gitIn("commit", "--allow-empty", "-m", "feat!: breaking change")
+	return dir, gitIn
+}
+
+// assertSameResults checks the go backend gives the same results as the cli backend
+func assertSameResults(t *testing.T, dir string) {
+	assert := assert.New(t)
+	cli, goRepo := NewVersionGitRepo(dir), &gitRepoGo{dir: dir}
+	same := func(name string, call func(r version.GitRepo) (interface{}, error)) {
+		expected, expectedErr := call(cli)
+		actual, actualErr := call(goRepo)
+		assert.Equal(expected, actual, name)
+		assert.Equal(expectedErr == nil, actualErr == nil, "%s: %v %v", name, expectedErr, actualErr)
+	}
+
+	ranges := [][2]string{{"", "HEAD"}, {"v0.1.0", "HEAD"}, {"v0.1.1", "HEAD"}, {"v0.2.0", "HEAD"}, {"v0.1.0", "v0.1.2"}, {"v0.1.2", "v0.2.0"}, {"HEAD~2", "feature/one"}, {"", "release/0.2"}}
+	for _, it := range ranges {
+		from, to := it[0], it[1]
+		same("GetCommits "+from+".."+to, func(r version.GitRepo) (interface{}, error) { return r.GetCommits(from, to) })
+		same("GetFirstParentCommits "+from+".."+to, func(r version.GitRepo) (interface{}, error) { return r.GetFirstParentCommits(from, to) })
+		same("CountCommits "+from+".."+to, func(r version.GitRepo) (interface{}, error) { return r.CountCommits(from, to) })
+	}
+	for _, rev := range []string{"HEAD", "HEAD^", "HEAD^2", "HEAD~3", "v0.2.0", "v0.2.0^", "v0.1.2", "v0.1.1", "v0.1.0", "v0.1.0^", "release/0.2", "feature/one"} {
+		same("GetLastRelativeTag "+rev, func(r version.GitRepo) (interface{}, error) { return r.GetLastRelativeTag(rev) })
+		same("GetTagsPointingAt "+rev, func(r version.GitRepo) (interface{}, error) { return r.GetTagsPointingAt(rev) })
+	}
+	same("GetTags", func(r version.GitRepo) (interface{}, error) { return r.GetTags() })
+	same("GetCurrentBranch", func(r version.GitRepo) (interface{}, error) { return r.GetCurrentBranch() })
+	same("GetRemoteURL", func(r version.GitRepo) (interface{}, error) { return r.GetRemoteURL() })
+	same("IsDirty", func(r version.GitRepo) (interface{}, error) { return r.IsDirty() })
+}
+
+func TestGitRepoGoLooseObjects(t *testing.T) {
+	dir, _ := newTestRepository(t)
+	assertSameResults(t, dir)
+}
+
+func TestGitRepoGoPackedObjects(t *testing.T) {
+	dir, gitIn := newTestRepository(t)
+	gitIn("gc", "--aggressive", "--prune=now")
+	_, err := os.Stat(filepath.Join(dir, ".git", "packed-refs"))
+	assert.NoError(t, err)
+	assertSameResults(t, dir)
+}
+
+func TestGitRepoGoShallowClone(t *testing.T) {
+	dir, gitIn := newTestRepository(t)
+	gitIn("remote", "add", "origin", "git@github.com:arnaud-deprez/gsemver.git")
+	gitIn("config", "url.https://github.com/.insteadOf", "git@github.com:")
+	clone := filepath.Join(t.TempDir(), "clone")
+	gitIn("clone", "--depth", "3", "--no-single-branch", "file://"+dir, clone)
+	assertSameResults(t, dir)
+	assertSameResults(t, clone)
+}
+
+func TestGitRepoGoDetachedHead(t *testing.T) {
+	dir, gitIn := newTestRepository(t)
+	gitIn("checkout", "--detach", "HEAD~1")
+	t.Setenv(ci.BranchEnv, "main")
+	assertSameResults(t, dir)
+
+	// without GIT_BRANCH, the branch is the one of the CI provider
+	t.Setenv(ci.BranchEnv, "")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REF_TYPE", "branch")
+	t.Setenv("GITHUB_HEAD_REF", "")
+	t.Setenv("GITHUB_REF_NAME", "release/0.2")
+	branch, err := NewVersionGitRepo(dir).GetCurrentBranch()
+	assert.NoError(t, err)
+	assert.Equal(t, "release/0.2", branch)
+	assertSameResults(t, dir)
+}
+
+func TestGitRepoGoCreateAndDeleteTag(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, gitIn := newTestRepository(t)
+	gitIn("pack-refs", "--all")
+	t.Setenv("GIT_COMMITTER_NAME", "gsemver")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gsemver@example.com")
+	t.Setenv("GIT_COMMITTER_DATE", "1700000000 +0100")
+	message := "## 1.0.0\n\n* #4"
+
+	// the tag object is the same as the one of git
+	cli, goRepo := NewVersionGitRepo(dir), &gitRepoGo{dir: dir}
+	assert.NoError(cli.CreateTag("tools/v1.0.0", message, false))
+	expected := gitIn("rev-parse", "tools/v1.0.0")
+	assert.NoError(cli.DeleteTag("tools/v1.0.0"))
+	assert.NoError(goRepo.CreateTag("tools/v1.0.0", message, false))
+	assert.Equal(expected, gitIn("rev-parse", "tools/v1.0.0"))
+	gitIn("fsck", "--strict")
+	assertSameResults(t, dir)
+	assert.EqualError(goRepo.CreateTag("tools/v1.0.0", message, false), "tag 'tools/v1.0.0' already exists")
+
+	// the tags can be deleted whether they are loose or packed
+	assert.NoError(goRepo.DeleteTag("tools/v1.0.0"))
+	assert.NoError(goRepo.DeleteTag("v0.1.0"))
+	assert.EqualError(goRepo.DeleteTag("v0.1.0"), "tag 'v0.1.0' not found")
+	assert.Equal("not-a-version\ntools/v0.1.1\nv0.1.1\nv0.1.2\nv0.2.0", gitIn("tag", "--list"))
+	assertSameResults(t, dir)
+
+	assert.EqualError(goRepo.CreateTag("v1.0.0", message, true), "signing tags is not supported by the go git backend, use the cli git backend")
+	assert.EqualError(goRepo.PushTag("v1.0.0"), "pushing tags is not supported by the go git backend, use the cli git backend")
+	assert.EqualError(goRepo.Commit("chore(release): v1.0.0"), "commit is not supported by the go git backend, use the cli git backend")
+}
+
+func TestNewVersionGitRepoWithBackend(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := NewVersionGitRepoWithBackend("", ".", version.FetchOptions{})
+	assert.NoError(err)
+	assert.IsType(&gitRepoCLI{}, r)
+	r, err = NewVersionGitRepoWithBackend(BackendGo, ".", version.FetchOptions{})
+	assert.NoError(err)
+	assert.IsType(&gitRepoGo{}, r)
+	_, err = NewVersionGitRepoWithBackend("jgit", ".", version.FetchOptions{})
+	assert.EqualError(err, `unknown git backend "jgit", expected one of cli, go`)
+}
+
+func TestIsDirty(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	gitIn := func(args ...string) string {
+		out, err := command.New("git").InDir(dir).WithArgs(append([]string{"-c", "user.name=gsemver", "-c", "user.email=gsemver@example.com"}, args...)...).Run()
+		assert.NoError(err, out)
+		return out
+	}
+	write := func(name string, content string) {
+		assert.NoError(os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		assert.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	cli, goRepo := NewVersionGitRepo(dir), &gitRepoGo{dir: dir}
+	assertDirty := func(expected bool, name string) {
+		dirty, err := cli.IsDirty()
+		assert.NoError(err, name)
+		assert.Equal(expected, dirty, "cli: %s", name)
+		// a new repository reads the index again
+		goRepo = &gitRepoGo{dir: dir}
+		dirty, err = goRepo.IsDirty()
+		assert.NoError(err, name)
+		assert.Equal(expected, dirty, "go: %s", name)
+	}
+	commit := func() {
+		gitIn("add", "--all")
+		gitIn("commit", "--allow-empty", "-m", "chore: commit")
+	}
+
+	gitIn("init", "--initial-branch", "main")
+	assertDirty(false, "no commit")
+	write("README.md", "# gsemver\n")
+	assertDirty(false, "untracked file")
+	gitIn("add", "README.md")
+	assertDirty(true, "new file")
+	write("cmd/main.go", "package main\n")
+	assert.NoError(os.Symlink("README.md", filepath.Join(dir, "LINK.md")))
+	commit()
+	assertDirty(false, "clean")
+
+	// the content is compared when the stat data changes
+	later := time.Now().Add(time.Hour)
+	assert.NoError(os.Chtimes(filepath.Join(dir, "README.md"), later, later))
+	assertDirty(false, "touched file")
+	write("README.md", "# gsemver!\n")
+	assertDirty(true, "modified file")
+	gitIn("add", "README.md")
+	assertDirty(true, "staged file")
+	commit()
+
+	assert.NoError(os.Chmod(filepath.Join(dir, "cmd/main.go"), 0o755))
+	assertDirty(true, "executable file")
+	commit()
+	assert.NoError(os.Remove(filepath.Join(dir, "LINK.md")))
+	assert.NoError(os.Symlink("cmd/main.go", filepath.Join(dir, "LINK.md")))
+	assertDirty(true, "symbolic link")
+	commit()
+	assert.NoError(os.Remove(filepath.Join(dir, "cmd/main.go")))
+	assertDirty(true, "deleted file")
+	gitIn("rm", "--cached", "cmd/main.go")
+	assertDirty(true, "removed file")
+	commit()
+	assertDirty(false, "clean after remove")
+
+	// the paths of the version 4 of the index are compressed
+	write("cmd/other.go", "package main\n")
+	write("cmd/sub/main.go", "package sub\n")
+	commit()
+	gitIn("update-index", "--index-version", "4")
+	gitIn("gc", "--prune=now")
+	assertDirty(false, "index version 4")
+	write("cmd/sub/main.go", "package main\n")
+	assertDirty(true, "index version 4 modified file")
+	gitIn("update-index", "--skip-worktree", "cmd/sub/main.go")
+	assertDirty(false, "skip worktree")
+}
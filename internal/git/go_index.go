@@ -0,0 +1,328 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	modeTree    = 0o040000
+	modeSymlink = 0o120000
+	modeGitlink = 0o160000
+	modeTypes   = 0o170000
+
+	indexEntryFlagAssumeValid  = 0x8000
+	indexEntryFlagExtended     = 0x4000
+	indexEntryFlagSkipWorktree = 0x4000
+	indexEntryFlagIntentToAdd  = 0x2000
+)
+
+// indexEntry is a file of the index
+type indexEntry struct {
+	path         string
+	mode         uint32
+	id           objectID
+	size         uint32
+	mtime        time.Time
+	stage        int
+	assumeValid  bool
+	skipWorktree bool
+	intentToAdd  bool
+}
+
+// gitIndex is the index of a repository, see https://git-scm.com/docs/index-format
+type gitIndex struct {
+	entries []indexEntry
+	// mtime is the modification time of the index file, the entries modified at the same time may be racily clean
+	mtime time.Time
+}
+
+// readIndex reads the index of a repository. A missing index is an empty index.
+func readIndex(path string) (*gitIndex, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &gitIndex{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12+hashSize || string(data[:4]) != "DIRC" {
+		return nil, fmt.Errorf("invalid index %s", path)
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("the version %d of the index %s is not supported by the go git backend", version, path)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+	index := &gitIndex{entries: make([]indexEntry, 0, count), mtime: info.ModTime()}
+
+	pos, end, previous := 12, len(data)-hashSize, ""
+	for i := 0; i < count; i++ {
+		if pos+62 > end {
+			return nil, fmt.Errorf("truncated index %s", path)
+		}
+		start := pos
+		e := indexEntry{
+			mtime: time.Unix(int64(binary.BigEndian.Uint32(data[pos+8:])), int64(binary.BigEndian.Uint32(data[pos+12:]))),
+			mode:  binary.BigEndian.Uint32(data[pos+24:]),
+			size:  binary.BigEndian.Uint32(data[pos+36:]),
+		}
+		copy(e.id[:], data[pos+40:pos+60])
+		flags := binary.BigEndian.Uint16(data[pos+60:])
+		e.assumeValid = flags&indexEntryFlagAssumeValid != 0
+		e.stage = int(flags>>12) & 0x3
+		pos += 62
+		if flags&indexEntryFlagExtended != 0 {
+			if version < 3 || pos+2 > end {
+				return nil, fmt.Errorf("invalid index %s", path)
+			}
+			extended := binary.BigEndian.Uint16(data[pos:])
+			e.skipWorktree = extended&indexEntryFlagSkipWorktree != 0
+			e.intentToAdd = extended&indexEntryFlagIntentToAdd != 0
+			pos += 2
+		}
+
+		if version == 4 {
+			// the path is prefix compressed: the number of bytes removed from the previous path then the suffix
+			remove, n := readOffsetVarint(data[pos:end])
+			if n == 0 || remove > len(previous) {
+				return nil, fmt.Errorf("invalid index %s", path)
+			}
+			pos += n
+			nul := bytes.IndexByte(data[pos:end], 0)
+			if nul < 0 {
+				return nil, fmt.Errorf("invalid index %s", path)
+			}
+			e.path = previous[:len(previous)-remove] + string(data[pos:pos+nul])
+			pos += nul + 1
+		} else {
+			nul := bytes.IndexByte(data[pos:end], 0)
+			if nul < 0 {
+				return nil, fmt.Errorf("invalid index %s", path)
+			}
+			e.path = string(data[pos : pos+nul])
+			// the entries are padded with 1 to 8 nul bytes to a multiple of 8 bytes
+			pos = start + (pos-start+nul+8)&^7
+		}
+		previous = e.path
+		index.entries = append(index.entries, e)
+	}
+
+	// the shared index of a split index holds the other entries
+	for pos+8 <= end {
+		signature, size := string(data[pos:pos+4]), int(binary.BigEndian.Uint32(data[pos+4:]))
+		if signature == "link" {
+			return nil, fmt.Errorf("the split index %s is not supported by the go git backend", path)
+		}
+		pos += 8 + size
+	}
+	return index, nil
+}
+
+// readOffsetVarint reads a variable length integer of git, the one of the offsets of ofs-delta objects.
+// It returns the value and the number of bytes read, 0 if it is truncated.
+func readOffsetVarint(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	value, n := int(c&0x7f), 1
+	for c&0x80 != 0 {
+		if n >= len(data) {
+			return 0, 0
+		}
+		c = data[n]
+		n++
+		value = ((value + 1) << 7) | int(c&0x7f)
+	}
+	return value, n
+}
+
+// treeEntry is a file of a tree
+type treeEntry struct {
+	mode uint32
+	id   objectID
+}
+
+// readTreeFiles reads the files of a tree and of its sub-trees by path
+func (r *goRepository) readTreeFiles(id objectID, prefix string, files map[string]treeEntry) error {
+	obj, err := r.objects.read(id)
+	if err != nil {
+		return err
+	}
+	if obj.typ != objTree {
+		return fmt.Errorf("object %s is a %s, not a tree", id, objTypeNames[obj.typ])
+	}
+	data := obj.data
+	for len(data) > 0 {
+		space := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if space < 0 || nul < space || nul+1+hashSize > len(data) {
+			return fmt.Errorf("invalid tree %s", id)
+		}
+		mode, err := strconv.ParseUint(string(data[:space]), 8, 32)
+		if err != nil {
+			return fmt.Errorf("invalid tree %s", id)
+		}
+		var e treeEntry
+		e.mode = uint32(mode)
+		copy(e.id[:], data[nul+1:nul+1+hashSize])
+		path := prefix + string(data[space+1:nul])
+		data = data[nul+1+hashSize:]
+
+		if e.mode&modeTypes == modeTree {
+			if err := r.readTreeFiles(e.id, path+"/", files); err != nil {
+				return err
+			}
+			continue
+		}
+		files[path] = e
+	}
+	return nil
+}
+
+// headTreeFiles returns the files of the tree of HEAD, none if HEAD has no commit yet
+func (r *goRepository) headTreeFiles() (map[string]treeEntry, error) {
+	files := map[string]treeEntry{}
+	id, err := r.refs.resolve("HEAD")
+	if errors.Is(err, errRefNotFound) {
+		return files, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj, err := r.objects.read(id)
+	if err != nil {
+		return nil, err
+	}
+	header, _, _ := strings.Cut(string(obj.data), "\n\n")
+	tree, ok := strings.CutPrefix(strings.SplitN(header, "\n", 2)[0], "tree ")
+	treeID, valid := parseObjectID(tree)
+	if !ok || !valid {
+		return nil, fmt.Errorf("invalid commit %s", id)
+	}
+	return files, r.readTreeFiles(treeID, "", files)
+}
+
+// isDirty compares the index with the tree of HEAD and the working tree with the index like git status does.
+// The untracked files and the changes of the submodules are ignored.
+func (r *goRepository) isDirty() (bool, error) {
+	if r.workTree == "" {
+		return false, errors.New("the status of a bare repository is not defined, it has no working tree")
+	}
+	index, err := readIndex(filepath.Join(r.gitDir, "index"))
+	if err != nil {
+		return false, err
+	}
+	head, err := r.headTreeFiles()
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range index.entries {
+		if e.mode&modeTypes == modeTree {
+			return false, errors.New("the sparse index is not supported by the go git backend")
+		}
+		// the conflicts and the files added with git add --intent-to-add are not committed
+		if e.stage != 0 || e.intentToAdd {
+			return true, nil
+		}
+		// the changes of the index
+		committed, ok := head[e.path]
+		if !ok || committed.id != e.id || committed.mode != e.mode {
+			return true, nil
+		}
+		delete(head, e.path)
+		// the changes of the working tree
+		if changed, err := r.isChanged(e, index.mtime); changed || err != nil {
+			return changed, err
+		}
+	}
+	// the files removed from the index
+	return len(head) > 0, nil
+}
+
+// isChanged compares a file of the working tree with its index entry
+func (r *goRepository) isChanged(e indexEntry, indexTime time.Time) (bool, error) {
+	if e.skipWorktree || e.assumeValid || e.mode&modeTypes == modeGitlink {
+		return false, nil
+	}
+	path := filepath.Join(r.workTree, filepath.FromSlash(e.path))
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var content []byte
+	switch {
+	case e.mode&modeTypes == modeSymlink:
+		if info.Mode()&os.ModeSymlink == 0 {
+			return true, nil
+		}
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		content = []byte(filepath.ToSlash(target))
+	case info.Mode().IsRegular():
+		fileMode := r.config.get("core.filemode")
+		if (fileMode == "" || isTrue(fileMode)) && (info.Mode()&0o100 != 0) != (e.mode&0o100 != 0) {
+			return true, nil
+		}
+		// like git, the stat data of the index avoids reading the files, unless they are racily clean
+		if uint32(info.Size()) == e.size && sameIndexTime(info.ModTime(), e.mtime) && info.ModTime().Before(indexTime) {
+			return false, nil
+		}
+		if content, err = os.ReadFile(path); err != nil {
+			return false, err
+		}
+		content = r.normalizeEOL(content)
+	default:
+		return true, nil
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	var id objectID
+	copy(id[:], h.Sum(nil))
+	return id != e.id, nil
+}
+
+// normalizeEOL converts the CRLF line endings of text content to LF when core.autocrlf is enabled.
+// The other conversions of the gitattributes, such as the clean filters, are not supported.
+func (r *goRepository) normalizeEOL(content []byte) []byte {
+	autoCRLF := strings.ToLower(r.config.get("core.autocrlf"))
+	if autoCRLF != "input" && !isTrue(autoCRLF) {
+		return content
+	}
+	// git considers the content with a nul byte in its first 8000 bytes as binary
+	if bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0 {
+		return content
+	}
+	return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+}
+
+// sameIndexTime compares the modification time of a file with the one of its index entry.
+// The nanoseconds are ignored if git did not record them.
+func sameIndexTime(fileTime time.Time, entryTime time.Time) bool {
+	if fileTime.Unix() != entryTime.Unix() {
+		return false
+	}
+	return entryTime.Nanosecond() == 0 || fileTime.Nanosecond() == entryTime.Nanosecond()
+}
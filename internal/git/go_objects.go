@@ -0,0 +1,497 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	objCommit   = 1
+	objTree     = 2
+	objBlob     = 3
+	objTag      = 4
+	objOfsDelta = 6
+	objRefDelta = 7
+
+	hashSize = sha1.Size
+	// maxBaseCacheSize is the number of delta bases kept in memory by pack
+	maxBaseCacheSize = 1024
+)
+
+var (
+	objTypeNames = map[int]string{objCommit: "commit", objTree: "tree", objBlob: "blob", objTag: "tag"}
+
+	errObjectNotFound = errors.New("object not found")
+)
+
+// objectID is the binary sha1 of a git object
+type objectID [hashSize]byte
+
+func (id objectID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// parseObjectID parses the hexadecimal form of a sha1
+func parseObjectID(s string) (objectID, bool) {
+	var id objectID
+	if len(s) != 2*hashSize {
+		return id, false
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, false
+	}
+	return id, true
+}
+
+// object is a decoded git object
+type object struct {
+	typ  int
+	data []byte
+}
+
+// objectStore reads the loose and packed objects of a repository and writes loose objects
+type objectStore struct {
+	dirs  []string
+	packs []*pack
+}
+
+// newObjectStore opens the objects directory and its alternates
+func newObjectStore(dir string) (*objectStore, error) {
+	s := &objectStore{}
+	if err := s.addDir(dir, 0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *objectStore) addDir(dir string, depth int) error {
+	// the same limit as git for the chains of alternates
+	if depth > 5 {
+		return fmt.Errorf("too many nested alternates in %s", dir)
+	}
+	s.dirs = append(s.dirs, dir)
+	idxs, err := filepath.Glob(filepath.Join(dir, "pack", "pack-*.idx"))
+	if err != nil {
+		return err
+	}
+	for _, idx := range idxs {
+		p, err := openPack(strings.TrimSuffix(idx, ".idx"))
+		if err != nil {
+			return err
+		}
+		p.store = s
+		s.packs = append(s.packs, p)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "info", "alternates"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(dir, line)
+		}
+		if err := s.addDir(line, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read reads an object from the loose objects or the packs
+func (s *objectStore) read(id objectID) (*object, error) {
+	for _, p := range s.packs {
+		if offset, ok := p.find(id); ok {
+			return p.read(offset)
+		}
+	}
+	for _, dir := range s.dirs {
+		obj, err := readLooseObject(filepath.Join(dir, id.String()[:2], id.String()[2:]))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read object %s: %v", id, err)
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("%w: %s", errObjectNotFound, id)
+}
+
+// has tells if the object exists
+func (s *objectStore) has(id objectID) bool {
+	for _, p := range s.packs {
+		if _, ok := p.find(id); ok {
+			return true
+		}
+	}
+	for _, dir := range s.dirs {
+		if _, err := os.Stat(filepath.Join(dir, id.String()[:2], id.String()[2:])); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// findPrefix returns the objects whose hexadecimal form starts with prefix
+func (s *objectStore) findPrefix(prefix string) []objectID {
+	found := map[objectID]bool{}
+	for _, p := range s.packs {
+		for _, id := range p.findPrefix(prefix) {
+			found[id] = true
+		}
+	}
+	for _, dir := range s.dirs {
+		entries, _ := os.ReadDir(filepath.Join(dir, prefix[:2]))
+		for _, e := range entries {
+			if name := prefix[:2] + e.Name(); strings.HasPrefix(name, prefix) {
+				if id, ok := parseObjectID(name); ok {
+					found[id] = true
+				}
+			}
+		}
+	}
+	ret := make([]objectID, 0, len(found))
+	for id := range found {
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+// write writes a loose object and returns its id
+func (s *objectStore) write(typ int, data []byte) (objectID, error) {
+	header := fmt.Sprintf("%s %d\x00", objTypeNames[typ], len(data))
+	h := sha1.New()
+	h.Write([]byte(header))
+	h.Write(data)
+	var id objectID
+	copy(id[:], h.Sum(nil))
+	if s.has(id) {
+		return id, nil
+	}
+
+	dir := filepath.Join(s.dirs[0], id.String()[:2])
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return id, err
+	}
+	f, err := os.CreateTemp(dir, "tmp_obj_")
+	if err != nil {
+		return id, err
+	}
+	defer os.Remove(f.Name())
+	zw := zlib.NewWriter(f)
+	zw.Write([]byte(header))
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return id, err
+	}
+	if err := f.Close(); err != nil {
+		return id, err
+	}
+	// the objects are read-only like the ones written by git
+	if err := os.Chmod(f.Name(), 0444); err != nil {
+		return id, err
+	}
+	return id, os.Rename(f.Name(), filepath.Join(dir, id.String()[2:]))
+}
+
+// readLooseObject reads a zlib compressed object with its header
+func readLooseObject(path string) (*object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	nul := bytes.IndexByte(content, 0)
+	if nul < 0 {
+		return nil, errors.New("invalid object header")
+	}
+	header := strings.SplitN(string(content[:nul]), " ", 2)
+	if len(header) != 2 {
+		return nil, errors.New("invalid object header")
+	}
+	typ := -1
+	for t, name := range objTypeNames {
+		if name == header[0] {
+			typ = t
+		}
+	}
+	size, err := strconv.Atoi(header[1])
+	if typ < 0 || err != nil || size != len(content)-nul-1 {
+		return nil, errors.New("invalid object header")
+	}
+	return &object{typ: typ, data: content[nul+1:]}, nil
+}
+
+// pack is a packfile with its index
+type pack struct {
+	store   *objectStore
+	file    *os.File
+	ids     []objectID
+	offsets []int64
+	// bases caches the delta bases by offset
+	bases map[int64]*object
+}
+
+// openPack reads the index of a pack, the pack is read on demand
+func openPack(path string) (*pack, error) {
+	idx, err := os.ReadFile(path + ".idx")
+	if err != nil {
+		return nil, err
+	}
+	p := &pack{bases: map[int64]*object{}}
+	if err := p.parseIndex(idx); err != nil {
+		return nil, fmt.Errorf("invalid pack index %s.idx: %v", path, err)
+	}
+	if p.file, err = os.Open(path + ".pack"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *pack) parseIndex(idx []byte) error {
+	if len(idx) < 8+256*4 {
+		return errors.New("truncated index")
+	}
+	version := 1
+	if bytes.Equal(idx[:4], []byte("\377tOc")) {
+		version = int(binary.BigEndian.Uint32(idx[4:8]))
+		if version != 2 {
+			return fmt.Errorf("unsupported version %d", version)
+		}
+		idx = idx[8:]
+	}
+	n := int(binary.BigEndian.Uint32(idx[255*4 : 256*4]))
+	idx = idx[256*4:]
+	p.ids = make([]objectID, n)
+	p.offsets = make([]int64, n)
+
+	if version == 1 {
+		// each entry is the 4-byte offset followed by the sha1
+		if len(idx) < n*(4+hashSize) {
+			return errors.New("truncated index")
+		}
+		for i := 0; i < n; i++ {
+			entry := idx[i*(4+hashSize):]
+			p.offsets[i] = int64(binary.BigEndian.Uint32(entry))
+			copy(p.ids[i][:], entry[4:4+hashSize])
+		}
+		return nil
+	}
+
+	// the sha1s, the crc32s, the 4-byte offsets then the 8-byte offsets
+	if len(idx) < n*(hashSize+4+4) {
+		return errors.New("truncated index")
+	}
+	for i := 0; i < n; i++ {
+		copy(p.ids[i][:], idx[i*hashSize:])
+	}
+	offsets := idx[n*(hashSize+4):]
+	large := offsets[n*4:]
+	for i := 0; i < n; i++ {
+		offset := binary.BigEndian.Uint32(offsets[i*4:])
+		if offset&0x80000000 == 0 {
+			p.offsets[i] = int64(offset)
+			continue
+		}
+		j := int(offset & 0x7fffffff)
+		if len(large) < (j+1)*8 {
+			return errors.New("truncated index")
+		}
+		p.offsets[i] = int64(binary.BigEndian.Uint64(large[j*8:]))
+	}
+	return nil
+}
+
+// find returns the offset of an object in the pack
+func (p *pack) find(id objectID) (int64, bool) {
+	i := sort.Search(len(p.ids), func(i int) bool { return bytes.Compare(p.ids[i][:], id[:]) >= 0 })
+	if i < len(p.ids) && p.ids[i] == id {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+func (p *pack) findPrefix(prefix string) []objectID {
+	var ret []objectID
+	i := sort.Search(len(p.ids), func(i int) bool { return p.ids[i].String() >= prefix })
+	for ; i < len(p.ids) && strings.HasPrefix(p.ids[i].String(), prefix); i++ {
+		ret = append(ret, p.ids[i])
+	}
+	return ret
+}
+
+// read reads the object at an offset and applies its deltas
+func (p *pack) read(offset int64) (*object, error) {
+	if obj, ok := p.bases[offset]; ok {
+		return obj, nil
+	}
+	r := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	typ := int(b>>4) & 7
+	// the size is not needed as zlib knows the end of the data
+	for b&0x80 != 0 {
+		if b, err = r.ReadByte(); err != nil {
+			return nil, err
+		}
+	}
+
+	var base *object
+	switch typ {
+	case objCommit, objTree, objBlob, objTag:
+		data, err := inflate(r)
+		if err != nil {
+			return nil, err
+		}
+		return &object{typ: typ, data: data}, nil
+	case objOfsDelta:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		rel := int64(b & 0x7f)
+		for b&0x80 != 0 {
+			if b, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+			rel = (rel+1)<<7 | int64(b&0x7f)
+		}
+		if base, err = p.read(offset - rel); err != nil {
+			return nil, err
+		}
+		p.cacheBase(offset-rel, base)
+	case objRefDelta:
+		var id objectID
+		if _, err := io.ReadFull(r, id[:]); err != nil {
+			return nil, err
+		}
+		if base, err = p.store.read(id); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid object type %d at offset %d", typ, offset)
+	}
+
+	delta, err := inflate(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := applyDelta(base.data, delta)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delta at offset %d: %v", offset, err)
+	}
+	return &object{typ: base.typ, data: data}, nil
+}
+
+func (p *pack) cacheBase(offset int64, obj *object) {
+	if len(p.bases) >= maxBaseCacheSize {
+		p.bases = map[int64]*object{}
+	}
+	p.bases[offset] = obj
+}
+
+func inflate(r io.Reader) ([]byte, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// applyDelta rebuilds an object from its base and a delta of copy and insert instructions
+func applyDelta(base, delta []byte) ([]byte, error) {
+	srcSize, delta := readDeltaSize(delta)
+	if srcSize != len(base) {
+		return nil, errors.New("base size mismatch")
+	}
+	dstSize, delta := readDeltaSize(delta)
+	ret := make([]byte, 0, dstSize)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		switch {
+		case op&0x80 != 0:
+			// the bits of the opcode tell which bytes of the offset and the size are present
+			var offset, size int
+			for i := 0; i < 7; i++ {
+				if op&(1<<i) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, errors.New("truncated copy instruction")
+				}
+				if i < 4 {
+					offset |= int(delta[0]) << (8 * i)
+				} else {
+					size |= int(delta[0]) << (8 * (i - 4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > len(base) {
+				return nil, errors.New("copy out of the base")
+			}
+			ret = append(ret, base[offset:offset+size]...)
+		case op != 0:
+			if int(op) > len(delta) {
+				return nil, errors.New("truncated insert instruction")
+			}
+			ret = append(ret, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, errors.New("reserved instruction")
+		}
+	}
+	if len(ret) != dstSize {
+		return nil, errors.New("result size mismatch")
+	}
+	return ret, nil
+}
+
+// readDeltaSize reads a little-endian base 128 size of a delta header
+func readDeltaSize(delta []byte) (int, []byte) {
+	size, shift := 0, 0
+	for i, b := range delta {
+		size |= int(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			return size, delta[i+1:]
+		}
+	}
+	return size, nil
+}
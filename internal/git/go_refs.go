@@ -0,0 +1,246 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var errRefNotFound = errors.New("reference not found")
+
+// ref is a reference with the object it points to
+type ref struct {
+	name string
+	id   objectID
+	// peeled is the commit of an annotated tag when known from packed-refs
+	peeled *objectID
+}
+
+// refStore reads the loose references and the packed-refs file
+type refStore struct {
+	gitDir    string
+	commonDir string
+}
+
+// symbolic returns the target of a symbolic reference such as HEAD or an empty string if it is not symbolic
+func (s *refStore) symbolic(name string) (string, error) {
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		return "", err
+	}
+	content := strings.TrimSpace(string(data))
+	if target, ok := strings.CutPrefix(content, "ref:"); ok {
+		return strings.TrimSpace(target), nil
+	}
+	return "", nil
+}
+
+// resolve resolves a reference, following the symbolic references
+func (s *refStore) resolve(name string) (objectID, error) {
+	for depth := 0; depth < 5; depth++ {
+		data, err := os.ReadFile(s.path(name))
+		if err == nil {
+			content := strings.TrimSpace(string(data))
+			if target, ok := strings.CutPrefix(content, "ref:"); ok {
+				name = strings.TrimSpace(target)
+				continue
+			}
+			if id, ok := parseObjectID(content); ok {
+				return id, nil
+			}
+			return objectID{}, fmt.Errorf("invalid reference %s", name)
+		}
+		// a missing loose reference may be packed
+		packed, err := s.packed()
+		if err != nil {
+			return objectID{}, err
+		}
+		if r, ok := packed[name]; ok {
+			return r.id, nil
+		}
+		return objectID{}, fmt.Errorf("%w: %s", errRefNotFound, name)
+	}
+	return objectID{}, fmt.Errorf("too many levels of symbolic references for %s", name)
+}
+
+// list lists the references with a prefix such as refs/tags/ sorted by name
+func (s *refStore) list(prefix string) ([]ref, error) {
+	refs, err := s.packed()
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Join(s.commonDir, filepath.FromSlash(prefix))
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".lock") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.commonDir, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		id, err := s.resolve(name)
+		if err != nil {
+			// like git, the broken references are ignored
+			return nil
+		}
+		r := ref{name: name, id: id}
+		// the peeled commit of packed-refs is still valid if the reference has not changed
+		if p, ok := refs[name]; ok && p.id == id {
+			r.peeled = p.peeled
+		}
+		refs[name] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []ref{}
+	for name, r := range refs {
+		if strings.HasPrefix(name, prefix) {
+			ret = append(ret, r)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].name < ret[j].name })
+	return ret, nil
+}
+
+// packed reads the packed-refs file
+func (s *refStore) packed() (map[string]ref, error) {
+	refs := map[string]ref{}
+	f, err := os.Open(filepath.Join(s.commonDir, "packed-refs"))
+	if os.IsNotExist(err) {
+		return refs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	last := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || line[0] == '#':
+		case line[0] == '^':
+			// the peeled object of the previous annotated tag
+			if id, ok := parseObjectID(line[1:]); ok && last != "" {
+				r := refs[last]
+				r.peeled = &id
+				refs[last] = r
+			}
+		default:
+			hash, name, found := strings.Cut(line, " ")
+			id, ok := parseObjectID(hash)
+			if !found || !ok {
+				return nil, fmt.Errorf("invalid line in packed-refs: %s", line)
+			}
+			refs[name] = ref{name: name, id: id}
+			last = name
+		}
+	}
+	return refs, scanner.Err()
+}
+
+// exists tells if a reference exists
+func (s *refStore) exists(name string) bool {
+	_, err := s.resolve(name)
+	return err == nil
+}
+
+// create creates a reference that must not exist
+func (s *refStore) create(name string, id objectID) error {
+	if s.exists(name) {
+		return fmt.Errorf("reference %s already exists", name)
+	}
+	path := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// the lock file makes sure no other process creates it in the meantime
+	lock, err := os.OpenFile(path+".lock", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot lock reference %s: %v", name, err)
+	}
+	defer os.Remove(lock.Name())
+	if _, err := lock.WriteString(id.String() + "\n"); err != nil {
+		lock.Close()
+		return err
+	}
+	if err := lock.Close(); err != nil {
+		return err
+	}
+	return os.Rename(lock.Name(), path)
+}
+
+// delete deletes a loose or packed reference
+func (s *refStore) delete(name string) error {
+	deleted := false
+	if err := os.Remove(s.path(name)); err == nil {
+		deleted = true
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	packedPath := filepath.Join(s.commonDir, "packed-refs")
+	data, err := os.ReadFile(packedPath)
+	if os.IsNotExist(err) {
+		data = nil
+	} else if err != nil {
+		return err
+	}
+	var kept []string
+	removing := false
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if removing && strings.HasPrefix(line, "^") {
+			continue
+		}
+		removing = strings.HasSuffix(strings.TrimSpace(line), " "+name)
+		if removing {
+			deleted = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", errRefNotFound, name)
+	}
+	if len(kept) == len(strings.SplitAfter(string(data), "\n")) {
+		return nil
+	}
+
+	lock, err := os.OpenFile(packedPath+".lock", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot lock packed-refs: %v", err)
+	}
+	defer os.Remove(lock.Name())
+	if _, err := lock.WriteString(strings.Join(kept, "")); err != nil {
+		lock.Close()
+		return err
+	}
+	if err := lock.Close(); err != nil {
+		return err
+	}
+	return os.Rename(lock.Name(), packedPath)
+}
+
+// path returns the path of a loose reference. HEAD is specific to a worktree, the other references are shared.
+func (s *refStore) path(name string) string {
+	if !strings.Contains(name, "/") {
+		return filepath.Join(s.gitDir, name)
+	}
+	return filepath.Join(s.commonDir, filepath.FromSlash(name))
+}
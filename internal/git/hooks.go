@@ -0,0 +1,22 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+)
+
+// GetHooksDir - use git rev-parse to retrieve the hooks directory of the repository in dir.
+// It takes core.hooksPath into account and always returns an absolute path.
+func GetHooksDir(dir string) (string, error) {
+	out, err := command.New("git").InDir(dir).WithArgs("rev-parse", "--git-path", "hooks").Run()
+	if err != nil {
+		return "", err
+	}
+	hooksDir := strings.TrimSpace(out)
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+const (
+	overlayAuthorName = "gsemver"
+	overlayHead       = "HEAD"
+)
+
+// NewOverlayVersionGitRepo creates a version.GitRepo that adds synthetic commits on top of HEAD of gitRepo.
+// The messages are committed in the given order, so the last message becomes the most recent commit.
+// The underlying repository is never modified.
+func NewOverlayVersionGitRepo(gitRepo version.GitRepo, messages ...string) version.GitRepo {
+	now := time.Now()
+	signature := git.Signature{Name: overlayAuthorName, When: now}
+	commits := make([]git.Commit, len(messages))
+	for i, message := range messages {
+		// git log returns the most recent commit first
+		commits[len(messages)-1-i] = git.Commit{
+			Hash:      syntheticHash(i, message),
+			Author:    signature,
+			Committer: signature,
+			Message:   message,
+		}
+	}
+	return &overlayGitRepo{
+		GitRepo: gitRepo,
+		commits: commits,
+	}
+}
+
+type overlayGitRepo struct {
+	version.GitRepo
+	commits []git.Commit
+}
+
+// GetCommits implements version.GitRepo.GetCommits
+// In a repository without commit yet, the synthetic commits are the only commits of HEAD.
+func (g *overlayGitRepo) GetCommits(from string, to string) ([]git.Commit, error) {
+	commits, err := g.GitRepo.GetCommits(from, to)
+	if !isHead(to) {
+		return commits, err
+	}
+	if err != nil {
+		log.Debug("GitRepo: no commit below the synthetic commits: %v", err)
+		commits = nil
+	}
+	return append(append([]git.Commit{}, g.commits...), commits...), nil
+}
+
+// CountCommits implements version.GitRepo.CountCommits
+// In a repository without commit yet, the synthetic commits are the only commits of HEAD.
+func (g *overlayGitRepo) CountCommits(from string, to string) (int, error) {
+	count, err := g.GitRepo.CountCommits(from, to)
+	if !isHead(to) {
+		return count, err
+	}
+	if err != nil {
+		log.Debug("GitRepo: no commit below the synthetic commits: %v", err)
+		count = 0
+	}
+	return count + len(g.commits), nil
+}
+
+func isHead(rev string) bool {
+	return rev == "" || rev == overlayHead
+}
+
+func syntheticHash(index int, message string) git.Hash {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%d\x00%s", index, message)))
+	return git.Hash(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestOverlayGitRepoGetCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{{Hash: "1234567890", Message: "fix: real"}}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.0", "v1.0.0").Times(1).Return([]git.Commit{{Hash: "0987654321", Message: "feat: past"}}, nil)
+
+	overlay := NewOverlayVersionGitRepo(gitRepo, "feat!: drop v1 API", "fix: typo")
+
+	commits, err := overlay.GetCommits("v1.0.0", "HEAD")
+	assert.NoError(err)
+	assert.Len(commits, 3)
+	// the most recent commit comes first like git log
+	assert.Equal("fix: typo", commits[0].Message)
+	assert.Equal("feat!: drop v1 API", commits[1].Message)
+	assert.Equal("fix: real", commits[2].Message)
+	assert.Len(commits[0].Hash.String(), 40)
+	assert.NotEqual(commits[0].Hash, commits[1].Hash)
+
+	// synthetic commits are only on top of HEAD
+	commits, err = overlay.GetCommits("v0.1.0", "v1.0.0")
+	assert.NoError(err)
+	assert.Len(commits, 1)
+}
+
+func TestOverlayGitRepoCountCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().CountCommits("v1.0.0", "").Times(1).Return(2, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+	overlay := NewOverlayVersionGitRepo(gitRepo, "feat: a")
+
+	count, err := overlay.CountCommits("v1.0.0", "")
+	assert.NoError(err)
+	assert.Equal(3, count)
+
+	// other methods are delegated
+	branch, err := overlay.GetCurrentBranch()
+	assert.NoError(err)
+	assert.Equal("main", branch)
+}
+
+func TestOverlayGitRepoWithoutCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	out, err := command.New("git").InDir(dir).WithArgs("init", "--initial-branch", "main").Run()
+	assert.NoError(err, out)
+
+	overlay := NewOverlayVersionGitRepo(NewVersionGitRepo(dir), "feat: first feature", "fix: typo")
+
+	// the repository has no commit yet, the synthetic commits are the whole history
+	commits, err := overlay.GetCommits("", "HEAD")
+	assert.NoError(err)
+	assert.Len(commits, 2)
+	count, err := overlay.CountCommits("", "HEAD")
+	assert.NoError(err)
+	assert.Equal(2, count)
+
+	v, err := version.NewConventionalCommitBumpStrategy(overlay).Bump()
+	assert.NoError(err)
+	assert.Equal("0.1.0", v.String())
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File is a manifest file containing the version of the project such as package.json or pom.xml
+type File struct {
+	// Path is the path of the file
+	Path string
+	// Format is the format of the file, see Formats. If empty, it is guessed from the name of the file.
+	Format string
+}
+
+// Formats returns the names of the supported formats
+func Formats() []string {
+	ret := make([]string, 0, len(updaters))
+	for name := range updaters {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Version reads the version of the file
+func (f File) Version() (string, error) {
+	content, u, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	start, end, err := u(content)
+	if err != nil {
+		return "", fmt.Errorf("cannot find the version in %s: %v", f.Path, err)
+	}
+	return content[start:end], nil
+}
+
+// Write replaces the version of the file, the rest of the file is left untouched.
+// It returns true if the file has changed.
+func (f File) Write(version string) (bool, error) {
+	content, u, err := f.read()
+	if err != nil {
+		return false, err
+	}
+	start, end, err := u(content)
+	if err != nil {
+		return false, fmt.Errorf("cannot find the version in %s: %v", f.Path, err)
+	}
+	if content[start:end] == version {
+		return false, nil
+	}
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return false, err
+	}
+	updated := content[:start] + version + content[end:]
+	if err := os.WriteFile(f.Path, []byte(updated), info.Mode()); err != nil {
+		return false, fmt.Errorf("cannot write %s: %v", f.Path, err)
+	}
+	return true, nil
+}
+
+// read reads the content of the file and returns it with the updater of its format
+func (f File) read() (string, updater, error) {
+	format := f.Format
+	if format == "" {
+		format = guessFormat(f.Path)
+		if format == "" {
+			return "", nil, fmt.Errorf("cannot guess the format of %s, expected one of %s", f.Path, strings.Join(Formats(), ", "))
+		}
+	}
+	u, ok := updaters[format]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown format %q of %s, expected one of %s", format, f.Path, strings.Join(Formats(), ", "))
+	}
+
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot read %s: %v", f.Path, err)
+	}
+	return string(data), u, nil
+}
+
+// guessFormat returns the format of a file from its name or an empty string if it is unknown
+func guessFormat(path string) string {
+	name := filepath.Base(path)
+	for format, names := range formatFileNames {
+		for _, it := range names {
+			if matched, _ := filepath.Match(it, name); matched {
+				return format
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "package.json")
+	assert.NoError(os.WriteFile(path, []byte("{\n  \"name\": \"app\",\n  \"version\": \"1.2.3\"\n}\n"), 0600))
+	f := File{Path: path}
+
+	v, err := f.Version()
+	assert.NoError(err)
+	assert.Equal("1.2.3", v)
+
+	changed, err := f.Write("1.3.0")
+	assert.NoError(err)
+	assert.True(changed)
+	content, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("{\n  \"name\": \"app\",\n  \"version\": \"1.3.0\"\n}\n", string(content))
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	changed, err = f.Write("1.3.0")
+	assert.NoError(err)
+	assert.False(changed)
+}
+
+func TestFileFormat(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"VERSION", "app.txt", "version.go"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("1.2.3\n"), 0644))
+	}
+
+	testData := []struct {
+		file        File
+		expected    string
+		expectedErr string
+	}{
+		{File{Path: filepath.Join(dir, "VERSION")}, "1.2.3", ""},
+		{File{Path: filepath.Join(dir, "app.txt"), Format: FormatText}, "1.2.3", ""},
+		{File{Path: filepath.Join(dir, "app.txt")}, "", "cannot guess the format of " + filepath.Join(dir, "app.txt") + ", expected one of cargo, go, helm, maven, npm, text"},
+		{File{Path: filepath.Join(dir, "app.txt"), Format: "gradle"}, "", `unknown format "gradle" of ` + filepath.Join(dir, "app.txt") + ", expected one of cargo, go, helm, maven, npm, text"},
+		{File{Path: filepath.Join(dir, "version.go")}, "", "cannot find the version in " + filepath.Join(dir, "version.go") + ": no Version constant or variable"},
+		{File{Path: filepath.Join(dir, "Chart.yaml")}, "", "cannot read " + filepath.Join(dir, "Chart.yaml") + ": open " + filepath.Join(dir, "Chart.yaml") + ": no such file or directory"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.file.Path, func(t *testing.T) {
+			v, err := tc.file.Version()
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
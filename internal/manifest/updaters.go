@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	// FormatNpm is the format of package.json: the top level version field
+	FormatNpm = "npm"
+	// FormatHelm is the format of Chart.yaml: the top level version field
+	FormatHelm = "helm"
+	// FormatMaven is the format of pom.xml: the version of the project
+	FormatMaven = "maven"
+	// FormatCargo is the format of Cargo.toml: the version of the package or of the workspace package
+	FormatCargo = "cargo"
+	// FormatText is the format of a file only containing the version such as VERSION
+	FormatText = "text"
+	// FormatGo is the format of a go source file declaring a Version constant or variable
+	FormatGo = "go"
+)
+
+// updater finds the span of the version in the content of a manifest
+type updater func(content string) (start int, end int, err error)
+
+var (
+	updaters = map[string]updater{
+		FormatNpm:   jsonVersion,
+		FormatHelm:  yamlVersion,
+		FormatMaven: pomVersion,
+		FormatCargo: cargoVersion,
+		FormatText:  textVersion,
+		FormatGo:    goVersion,
+	}
+	// formatFileNames are the file name patterns of each format
+	formatFileNames = map[string][]string{
+		FormatNpm:   {"package.json"},
+		FormatHelm:  {"Chart.yaml"},
+		FormatMaven: {"pom.xml"},
+		FormatCargo: {"Cargo.toml"},
+		FormatText:  {"VERSION", "VERSION.txt"},
+		FormatGo:    {"*.go"},
+	}
+
+	errVersionNotFound = errors.New("no version field")
+
+	// yamlVersionRegex matches the top level version field of a YAML document
+	yamlVersionRegex = regexp.MustCompile(`(?m)^version:[ \t]*(?:"([^"\n]*)"|'([^'\n]*)'|([^\s#'"][^\s#]*))`)
+	// tomlTableRegex matches a TOML table header such as [package] or [[bin]]
+	tomlTableRegex = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]`)
+	// tomlVersionRegex matches the version key of a TOML table
+	tomlVersionRegex = regexp.MustCompile(`^\s*version\s*=\s*"([^"]*)"`)
+	// goVersionRegex matches the declaration of the Version constant or variable of a go source file
+	goVersionRegex = regexp.MustCompile(`(?m)^\s*(?:(?:const|var)\s+)?Version(?:\s+string)?\s*=\s*"([^"\n]*)"`)
+)
+
+// jsonVersion finds the top level version field of a JSON object
+func jsonVersion(content string) (int, int, error) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return 0, 0, errors.New("not a JSON object")
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+		if key != "version" {
+			if err := skipJSONValue(dec); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+		value, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+		if _, ok := value.(string); !ok {
+			return 0, 0, errors.New("version is not a string")
+		}
+		// the offset is right after the closing quote of the version
+		end := int(dec.InputOffset()) - 1
+		start := strings.LastIndex(content[:end], `"`) + 1
+		return start, end, nil
+	}
+	return 0, 0, errVersionNotFound
+}
+
+// skipJSONValue reads the next value, including the nested ones
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// yamlVersion finds the top level version field of a YAML document
+func yamlVersion(content string) (int, int, error) {
+	m := yamlVersionRegex.FindStringSubmatchIndex(content)
+	if m == nil {
+		return 0, 0, errVersionNotFound
+	}
+	// the version is the group of the quotes used, if any
+	for i := 2; i < len(m); i += 2 {
+		if m[i] >= 0 {
+			return m[i], m[i+1], nil
+		}
+	}
+	return 0, 0, errVersionNotFound
+}
+
+// pomVersion finds the version element of the project, not the one of its parent or dependencies
+func pomVersion(content string) (int, int, error) {
+	dec := xml.NewDecoder(strings.NewReader(content))
+	var path []string
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return 0, 0, errVersionNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		switch e := t.(type) {
+		case xml.StartElement:
+			path = append(path, e.Name.Local)
+			if strings.Join(path, "/") != "project/version" {
+				continue
+			}
+			start := int(dec.InputOffset())
+			if t, err = dec.Token(); err != nil {
+				return 0, 0, err
+			}
+			if _, ok := t.(xml.CharData); !ok {
+				return 0, 0, errors.New("the version of the project is empty")
+			}
+			value := content[start:dec.InputOffset()]
+			// the version is kept without the surrounding spaces
+			start += len(value) - len(strings.TrimLeft(value, " \t\r\n"))
+			return start, start + len(strings.TrimSpace(value)), nil
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// cargoVersion finds the version of the package table or of the workspace.package table
+func cargoVersion(content string) (int, int, error) {
+	table := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if m := tomlTableRegex.FindStringSubmatch(line); m != nil {
+			table = m[1]
+		} else if table == "package" || table == "workspace.package" {
+			if m := tomlVersionRegex.FindStringSubmatchIndex(line); m != nil {
+				return offset + m[2], offset + m[3], nil
+			}
+		}
+		offset += len(line)
+	}
+	return 0, 0, errVersionNotFound
+}
+
+// textVersion finds the version of a file only containing the version, without the surrounding spaces
+func textVersion(content string) (int, int, error) {
+	start := len(content) - len(strings.TrimLeft(content, " \t\r\n"))
+	end := len(strings.TrimRight(content, " \t\r\n"))
+	if start >= end {
+		return 0, 0, errors.New("the file is empty")
+	}
+	return start, end, nil
+}
+
+// goVersion finds the value of the Version constant or variable of a go source file
+func goVersion(content string) (int, int, error) {
+	m := goVersionRegex.FindStringSubmatchIndex(content)
+	if m == nil {
+		return 0, 0, errors.New("no Version constant or variable")
+	}
+	return m[2], m[3], nil
+}
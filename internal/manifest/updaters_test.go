@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdaters(t *testing.T) {
+	testData := []struct {
+		name     string
+		format   string
+		content  string
+		expected string
+	}{
+		{"npm", FormatNpm, `{
+  "name": "app",
+  "config": {"version": "0.0.1"},
+  "files": ["version"],
+  "version":   "1.2.3",
+  "dependencies": {
+    "lib": "^1.0.0"
+  }
+}
+`, `{
+  "name": "app",
+  "config": {"version": "0.0.1"},
+  "files": ["version"],
+  "version":   "2.0.0",
+  "dependencies": {
+    "lib": "^1.0.0"
+  }
+}
+`},
+		{"helm", FormatHelm, `apiVersion: v2
+name: app
+# version: 0.0.1
+dependencies:
+  - name: lib
+    version: 1.0.0
+version: 1.2.3 # chart version
+appVersion: "1.2.3"
+`, `apiVersion: v2
+name: app
+# version: 0.0.1
+dependencies:
+  - name: lib
+    version: 1.0.0
+version: 2.0.0 # chart version
+appVersion: "1.2.3"
+`},
+		{"helm quoted", FormatHelm, "name: app\nversion: '1.2.3'\n", "name: app\nversion: '2.0.0'\n"},
+		{"maven", FormatMaven, `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent>
+    <groupId>org.example</groupId>
+    <version>0.0.1</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <version>
+    1.2.3
+  </version>
+  <dependencies>
+    <dependency>
+      <version>1.0.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`, `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent>
+    <groupId>org.example</groupId>
+    <version>0.0.1</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <version>
+    2.0.0
+  </version>
+  <dependencies>
+    <dependency>
+      <version>1.0.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`},
+		{"cargo", FormatCargo, `[package]
+name = "app"
+version   = "1.2.3" # the version
+edition = "2021"
+
+[dependencies]
+lib = { version = "1.0.0" }
+`, `[package]
+name = "app"
+version   = "2.0.0" # the version
+edition = "2021"
+
+[dependencies]
+lib = { version = "1.0.0" }
+`},
+		{"cargo workspace", FormatCargo, `[workspace]
+members = ["app"]
+
+[workspace.dependencies]
+version = "0.0.1"
+
+[workspace.package]
+version = "1.2.3"
+`, `[workspace]
+members = ["app"]
+
+[workspace.dependencies]
+version = "0.0.1"
+
+[workspace.package]
+version = "2.0.0"
+`},
+		{"text", FormatText, "  1.2.3\n\n", "  2.0.0\n\n"},
+		{"go const", FormatGo, "package version\n\n// Version is the version\nconst Version = \"1.2.3\"\n", "package version\n\n// Version is the version\nconst Version = \"2.0.0\"\n"},
+		{"go const block", FormatGo, "package version\n\nconst (\n\tName    = \"app\"\n\tVersion string = \"1.2.3\"\n)\n", "package version\n\nconst (\n\tName    = \"app\"\n\tVersion string = \"2.0.0\"\n)\n"},
+		{"go var", FormatGo, "package main\n\nvar Version = \"dev\"\n", "package main\n\nvar Version = \"2.0.0\"\n"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			start, end, err := updaters[tc.format](tc.content)
+			assert.NoError(err)
+			assert.Equal(tc.expected, tc.content[:start]+"2.0.0"+tc.content[end:])
+		})
+	}
+}
+
+func TestUpdatersWithoutVersion(t *testing.T) {
+	testData := []struct {
+		format   string
+		content  string
+		expected string
+	}{
+		{FormatNpm, `{"name": "app", "config": {"version": "1.0.0"}}`, "no version field"},
+		{FormatNpm, `{"version": 1}`, "version is not a string"},
+		{FormatNpm, `["version"]`, "not a JSON object"},
+		{FormatHelm, "name: app\ndependencies:\n  - version: 1.0.0\n", "no version field"},
+		{FormatMaven, "<project><parent><version>1.0.0</version></parent></project>", "no version field"},
+		{FormatMaven, "<project><version/></project>", "the version of the project is empty"},
+		{FormatCargo, "[package]\nname = \"app\"\nversion.workspace = true\n", "no version field"},
+		{FormatText, " \n", "the file is empty"},
+		{FormatGo, "package version\n\nconst Name = \"app\"\n", "no Version constant or variable"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.format, func(t *testing.T) {
+			_, _, err := updaters[tc.format](tc.content)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
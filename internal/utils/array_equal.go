@@ -12,3 +12,13 @@ func ArrayStringEqual(a, b []string) bool {
 	}
 	return true
 }
+
+// ContainsString returns true if the array of string contains the value
+func ContainsString(a []string, value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
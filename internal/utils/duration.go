@@ -0,0 +1,19 @@
+package utils
+
+import "time"
+
+// DurationToString converts time.Duration to string or returns an empty string for a zero duration
+func DurationToString(d time.Duration) string {
+	if d != 0 {
+		return d.String()
+	}
+	return ""
+}
+
+// ParseDuration parses a duration string such as 30s or returns a zero duration for an empty string
+func ParseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
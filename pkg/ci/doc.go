@@ -0,0 +1,2 @@
+// Package ci detects the CI provider running the build and the branch it builds from its environment variables.
+package ci
@@ -0,0 +1,316 @@
+package ci
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BranchEnv is the environment variable that overrides the branch detected from the CI provider
+	BranchEnv = "GIT_BRANCH"
+
+	// GitHubActions is the name of the GitHub Actions provider
+	GitHubActions = "github-actions"
+	// GitLab is the name of the GitLab CI provider
+	GitLab = "gitlab"
+	// Jenkins is the name of the Jenkins provider
+	Jenkins = "jenkins"
+	// AzurePipelines is the name of the Azure Pipelines provider
+	AzurePipelines = "azure-pipelines"
+	// CircleCI is the name of the CircleCI provider
+	CircleCI = "circleci"
+	// Bitbucket is the name of the Bitbucket Pipelines provider
+	Bitbucket = "bitbucket"
+	// Buildkite is the name of the Buildkite provider
+	Buildkite = "buildkite"
+	// Drone is the name of the Drone provider
+	Drone = "drone"
+	// Tekton is the name of the Tekton provider
+	Tekton = "tekton"
+)
+
+var (
+	// pullRequestRefRegex matches the references of the pull requests such as refs/pull/42/merge
+	/* const */ pullRequestRefRegex = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+	// tektonDir is the directory Tekton mounts in every step, Tekton does not set any environment variable
+	tektonDir = "/tekton"
+
+	// providers are the supported CI providers, in the order they are detected
+	providers = []provider{
+		{
+			name:   GitHubActions,
+			detect: isTrue("GITHUB_ACTIONS"),
+			// GITHUB_REF_NAME is <pr>/merge for pull requests and the tag name for tags, GITHUB_HEAD_REF is the source branch of pull requests
+			branch: func(getenv func(string) string) string {
+				if getenv("GITHUB_REF_TYPE") == "tag" {
+					return ""
+				}
+				return firstOf(getenv, "GITHUB_HEAD_REF", "GITHUB_REF_NAME")
+			},
+			// GITHUB_HEAD_REF and GITHUB_BASE_REF are only set for pull_request and pull_request_target events.
+			// GITHUB_REF is the target branch for pull_request_target events, the number is then read from the event.
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("GITHUB_HEAD_REF") == "" {
+					return nil
+				}
+				number := pullRequestNumberFromRef(getenv("GITHUB_REF"))
+				if number == "" {
+					number = pullRequestNumberFromEvent(getenv("GITHUB_EVENT_PATH"))
+				}
+				return &PullRequest{Number: number, SourceBranch: getenv("GITHUB_HEAD_REF"), TargetBranch: getenv("GITHUB_BASE_REF")}
+			},
+		},
+		{
+			name:   GitLab,
+			detect: isTrue("GITLAB_CI"),
+			// CI_COMMIT_REF_NAME is the tag name for tag pipelines
+			branch: func(getenv func(string) string) string {
+				if getenv("CI_COMMIT_TAG") != "" {
+					return ""
+				}
+				return firstOf(getenv, "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "CI_COMMIT_BRANCH", "CI_COMMIT_REF_NAME")
+			},
+			// the external pull requests are the GitHub pull requests of a GitLab CI/CD for external repositories
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("CI_MERGE_REQUEST_IID") != "" {
+					return &PullRequest{Number: getenv("CI_MERGE_REQUEST_IID"), SourceBranch: getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"), TargetBranch: getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME")}
+				}
+				if getenv("CI_EXTERNAL_PULL_REQUEST_IID") != "" {
+					return &PullRequest{Number: getenv("CI_EXTERNAL_PULL_REQUEST_IID"), SourceBranch: getenv("CI_EXTERNAL_PULL_REQUEST_SOURCE_BRANCH_NAME"), TargetBranch: getenv("CI_EXTERNAL_PULL_REQUEST_TARGET_BRANCH_NAME")}
+				}
+				return nil
+			},
+		},
+		{
+			name:   Jenkins,
+			detect: isSet("JENKINS_URL"),
+			// BRANCH_NAME is PR-<number> for the pull requests of a multibranch pipeline, CHANGE_BRANCH is their source branch
+			branch: func(getenv func(string) string) string {
+				return firstOf(getenv, "CHANGE_BRANCH", "BRANCH_NAME")
+			},
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("CHANGE_ID") == "" {
+					return nil
+				}
+				return &PullRequest{Number: getenv("CHANGE_ID"), SourceBranch: getenv("CHANGE_BRANCH"), TargetBranch: getenv("CHANGE_TARGET")}
+			},
+		},
+		{
+			name:   AzurePipelines,
+			detect: isTrue("TF_BUILD"),
+			// BUILD_SOURCEBRANCH is refs/pull/<number>/merge for pull requests and refs/tags/<tag> for tags
+			branch: func(getenv func(string) string) string {
+				if ref := getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"); ref != "" {
+					return strings.TrimPrefix(ref, "refs/heads/")
+				}
+				if ref, ok := strings.CutPrefix(getenv("BUILD_SOURCEBRANCH"), "refs/heads/"); ok {
+					return ref
+				}
+				return ""
+			},
+			// SYSTEM_PULLREQUEST_PULLREQUESTNUMBER is only set for GitHub repositories and is the number of the pull request.
+			// The one of Azure Repos is SYSTEM_PULLREQUEST_PULLREQUESTID.
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("BUILD_REASON") != "PullRequest" {
+					return nil
+				}
+				return &PullRequest{
+					Number:       firstOf(getenv, "SYSTEM_PULLREQUEST_PULLREQUESTNUMBER", "SYSTEM_PULLREQUEST_PULLREQUESTID"),
+					SourceBranch: strings.TrimPrefix(getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"), "refs/heads/"),
+					TargetBranch: strings.TrimPrefix(getenv("SYSTEM_PULLREQUEST_TARGETBRANCH"), "refs/heads/"),
+				}
+			},
+		},
+		{
+			name:   CircleCI,
+			detect: isTrue("CIRCLECI"),
+			// CIRCLE_BRANCH is not set for tags
+			branch: func(getenv func(string) string) string {
+				return getenv("CIRCLE_BRANCH")
+			},
+			// CIRCLE_PULL_REQUEST is the URL of the pull request, CircleCI does not give its target branch
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				url := getenv("CIRCLE_PULL_REQUEST")
+				if url == "" {
+					return nil
+				}
+				return &PullRequest{Number: url[strings.LastIndex(url, "/")+1:], SourceBranch: getenv("CIRCLE_BRANCH")}
+			},
+		},
+		{
+			name:   Bitbucket,
+			detect: isSet("BITBUCKET_BUILD_NUMBER"),
+			// BITBUCKET_BRANCH is the source branch for pull requests and is not set for tags
+			branch: func(getenv func(string) string) string {
+				return getenv("BITBUCKET_BRANCH")
+			},
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("BITBUCKET_PR_ID") == "" {
+					return nil
+				}
+				return &PullRequest{Number: getenv("BITBUCKET_PR_ID"), SourceBranch: getenv("BITBUCKET_BRANCH"), TargetBranch: getenv("BITBUCKET_PR_DESTINATION_BRANCH")}
+			},
+		},
+		{
+			name:   Buildkite,
+			detect: isTrue("BUILDKITE"),
+			// BUILDKITE_BRANCH is the source branch for pull requests and the tag name for tags
+			branch: func(getenv func(string) string) string {
+				if getenv("BUILDKITE_TAG") != "" {
+					return ""
+				}
+				return getenv("BUILDKITE_BRANCH")
+			},
+			// BUILDKITE_PULL_REQUEST is false when the build is not a pull request
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if number := getenv("BUILDKITE_PULL_REQUEST"); number == "" || number == "false" {
+					return nil
+				}
+				return &PullRequest{Number: getenv("BUILDKITE_PULL_REQUEST"), SourceBranch: getenv("BUILDKITE_BRANCH"), TargetBranch: getenv("BUILDKITE_PULL_REQUEST_BASE_BRANCH")}
+			},
+		},
+		{
+			name:   Drone,
+			detect: isTrue("DRONE"),
+			// DRONE_BRANCH is the target branch for pull requests, DRONE_SOURCE_BRANCH is their source branch
+			branch: func(getenv func(string) string) string {
+				if getenv("DRONE_TAG") != "" {
+					return ""
+				}
+				return firstOf(getenv, "DRONE_SOURCE_BRANCH", "DRONE_BRANCH")
+			},
+			pullRequest: func(getenv func(string) string) *PullRequest {
+				if getenv("DRONE_PULL_REQUEST") == "" {
+					return nil
+				}
+				return &PullRequest{Number: getenv("DRONE_PULL_REQUEST"), SourceBranch: getenv("DRONE_SOURCE_BRANCH"), TargetBranch: getenv("DRONE_TARGET_BRANCH")}
+			},
+		},
+		{
+			name: Tekton,
+			detect: func(_ func(string) string) bool {
+				_, err := os.Stat(tektonDir)
+				return err == nil
+			},
+			// the branch is a parameter of the pipeline that must be mapped to GIT_BRANCH
+			branch: func(_ func(string) string) string {
+				return ""
+			},
+		},
+	}
+)
+
+// Provider is the CI provider running the build
+type Provider struct {
+	// Name is the name of the CI provider, empty if the build does not run on a supported CI provider
+	Name string `json:"name"`
+	// Branch is the branch built according to the environment variables of the CI provider.
+	// For pull requests, it is their source branch. It is empty for tags.
+	Branch string `json:"branch"`
+	// PullRequest is the pull request built, nil if the build is not a pull request or merge request
+	PullRequest *PullRequest `json:"pullRequest,omitempty"`
+}
+
+// PullRequest is a pull request or a merge request built by the CI provider
+type PullRequest struct {
+	// Number is the number of the pull request, eg. 42. It may be empty if the CI provider does not give it.
+	Number string `json:"number"`
+	// SourceBranch is the branch of the changes of the pull request
+	SourceBranch string `json:"sourceBranch"`
+	// TargetBranch is the branch the pull request is merged into. It may be empty if the CI provider does not give it.
+	TargetBranch string `json:"targetBranch"`
+}
+
+// provider detects a CI provider, the branch and the pull request it builds from the environment variables
+type provider struct {
+	name        string
+	detect      func(getenv func(string) string) bool
+	branch      func(getenv func(string) string) string
+	pullRequest func(getenv func(string) string) *PullRequest
+}
+
+// Detect detects the CI provider from the environment variables of the process
+func Detect() Provider {
+	return DetectWithEnv(os.Getenv)
+}
+
+// DetectWithEnv detects the CI provider from the environment variables given by getenv.
+// It returns a Provider with an empty name if none of the supported CI providers is detected.
+func DetectWithEnv(getenv func(string) string) Provider {
+	trimmed := func(key string) string {
+		return strings.TrimSpace(getenv(key))
+	}
+	for _, p := range providers {
+		if p.detect(trimmed) {
+			ret := Provider{Name: p.name, Branch: p.branch(trimmed)}
+			if p.pullRequest != nil {
+				ret.PullRequest = p.pullRequest(trimmed)
+			}
+			return ret
+		}
+	}
+	return Provider{}
+}
+
+// CurrentBranch gives the branch built by the CI from the environment variables of the process.
+// The GIT_BRANCH environment variable takes precedence over the ones of the CI provider.
+func CurrentBranch() string {
+	if branch := strings.TrimSpace(os.Getenv(BranchEnv)); branch != "" {
+		return branch
+	}
+	return Detect().Branch
+}
+
+// isTrue detects a provider with an environment variable set to true
+func isTrue(key string) func(getenv func(string) string) bool {
+	return func(getenv func(string) string) bool {
+		return strings.EqualFold(getenv(key), "true")
+	}
+}
+
+// isSet detects a provider with an environment variable that is not empty
+func isSet(key string) func(getenv func(string) string) bool {
+	return func(getenv func(string) string) bool {
+		return getenv(key) != ""
+	}
+}
+
+// firstOf returns the value of the first environment variable that is not empty
+func firstOf(getenv func(string) string, keys ...string) string {
+	for _, key := range keys {
+		if value := getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// pullRequestNumberFromRef returns the number of a pull request reference such as refs/pull/42/merge
+func pullRequestNumberFromRef(ref string) string {
+	if m := pullRequestRefRegex.FindStringSubmatch(ref); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pullRequestNumberFromEvent returns the number of the pull request of the event payload of GitHub Actions
+func pullRequestNumberFromEvent(path string) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var event struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil || event.Number == 0 {
+		return ""
+	}
+	return strconv.Itoa(event.Number)
+}
@@ -0,0 +1,86 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectWithEnv(t *testing.T) {
+	testData := []struct {
+		name     string
+		env      map[string]string
+		expected Provider
+	}{
+		{"none", map[string]string{"CI": "true"}, Provider{}},
+		{"github push", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF_TYPE": "branch", "GITHUB_REF_NAME": "main"}, Provider{Name: GitHubActions, Branch: "main"}},
+		{"github pull request", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF_TYPE": "branch", "GITHUB_REF": "refs/pull/12/merge", "GITHUB_REF_NAME": "12/merge", "GITHUB_HEAD_REF": "feature/one", "GITHUB_BASE_REF": "main"}, Provider{Name: GitHubActions, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"github tag", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF_TYPE": "tag", "GITHUB_REF_NAME": "v1.0.0"}, Provider{Name: GitHubActions}},
+		{"gitlab push", map[string]string{"GITLAB_CI": "true", "CI_COMMIT_REF_NAME": "main", "CI_COMMIT_BRANCH": "main"}, Provider{Name: GitLab, Branch: "main"}},
+		{"gitlab merge request", map[string]string{"GITLAB_CI": "true", "CI_COMMIT_REF_NAME": "feature/one", "CI_MERGE_REQUEST_IID": "12", "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature/one", "CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main"}, Provider{Name: GitLab, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"gitlab external pull request", map[string]string{"GITLAB_CI": "true", "CI_COMMIT_REF_NAME": "feature/one", "CI_COMMIT_BRANCH": "feature/one", "CI_EXTERNAL_PULL_REQUEST_IID": "12", "CI_EXTERNAL_PULL_REQUEST_SOURCE_BRANCH_NAME": "feature/one", "CI_EXTERNAL_PULL_REQUEST_TARGET_BRANCH_NAME": "main"}, Provider{Name: GitLab, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"gitlab ref name", map[string]string{"GITLAB_CI": "true", "CI_COMMIT_REF_NAME": "release/1.x"}, Provider{Name: GitLab, Branch: "release/1.x"}},
+		{"gitlab tag", map[string]string{"GITLAB_CI": "true", "CI_COMMIT_REF_NAME": "v1.0.0", "CI_COMMIT_TAG": "v1.0.0"}, Provider{Name: GitLab}},
+		{"jenkins", map[string]string{"JENKINS_URL": "https://jenkins.example.com/", "BRANCH_NAME": "main"}, Provider{Name: Jenkins, Branch: "main"}},
+		{"jenkins pull request", map[string]string{"JENKINS_URL": "https://jenkins.example.com/", "BRANCH_NAME": "PR-12", "CHANGE_ID": "12", "CHANGE_BRANCH": "feature/one", "CHANGE_TARGET": "main"}, Provider{Name: Jenkins, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"azure", map[string]string{"TF_BUILD": "True", "BUILD_SOURCEBRANCH": "refs/heads/release/1.x", "BUILD_SOURCEBRANCHNAME": "1.x"}, Provider{Name: AzurePipelines, Branch: "release/1.x"}},
+		{"azure pull request", map[string]string{"TF_BUILD": "True", "BUILD_REASON": "PullRequest", "BUILD_SOURCEBRANCH": "refs/pull/12/merge", "SYSTEM_PULLREQUEST_PULLREQUESTID": "1234", "SYSTEM_PULLREQUEST_PULLREQUESTNUMBER": "12", "SYSTEM_PULLREQUEST_SOURCEBRANCH": "refs/heads/feature/one", "SYSTEM_PULLREQUEST_TARGETBRANCH": "refs/heads/main"}, Provider{Name: AzurePipelines, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"azure repos pull request", map[string]string{"TF_BUILD": "True", "BUILD_REASON": "PullRequest", "BUILD_SOURCEBRANCH": "refs/pull/1234/merge", "SYSTEM_PULLREQUEST_PULLREQUESTID": "1234", "SYSTEM_PULLREQUEST_SOURCEBRANCH": "refs/heads/feature/one", "SYSTEM_PULLREQUEST_TARGETBRANCH": "main"}, Provider{Name: AzurePipelines, Branch: "feature/one", PullRequest: &PullRequest{Number: "1234", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"azure tag", map[string]string{"TF_BUILD": "True", "BUILD_SOURCEBRANCH": "refs/tags/v1.0.0"}, Provider{Name: AzurePipelines}},
+		{"circleci", map[string]string{"CIRCLECI": "true", "CIRCLE_BRANCH": "main"}, Provider{Name: CircleCI, Branch: "main"}},
+		{"circleci pull request", map[string]string{"CIRCLECI": "true", "CIRCLE_BRANCH": "feature/one", "CIRCLE_PULL_REQUEST": "https://github.com/arnaud-deprez/gsemver/pull/12"}, Provider{Name: CircleCI, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one"}}},
+		{"bitbucket", map[string]string{"BITBUCKET_BUILD_NUMBER": "42", "BITBUCKET_BRANCH": "main"}, Provider{Name: Bitbucket, Branch: "main"}},
+		{"bitbucket pull request", map[string]string{"BITBUCKET_BUILD_NUMBER": "42", "BITBUCKET_BRANCH": "feature/one", "BITBUCKET_PR_ID": "12", "BITBUCKET_PR_DESTINATION_BRANCH": "main"}, Provider{Name: Bitbucket, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"buildkite", map[string]string{"BUILDKITE": "true", "BUILDKITE_BRANCH": "main", "BUILDKITE_PULL_REQUEST": "false"}, Provider{Name: Buildkite, Branch: "main"}},
+		{"buildkite pull request", map[string]string{"BUILDKITE": "true", "BUILDKITE_BRANCH": "feature/one", "BUILDKITE_PULL_REQUEST": "12", "BUILDKITE_PULL_REQUEST_BASE_BRANCH": "main"}, Provider{Name: Buildkite, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"buildkite tag", map[string]string{"BUILDKITE": "true", "BUILDKITE_BRANCH": "v1.0.0", "BUILDKITE_TAG": "v1.0.0"}, Provider{Name: Buildkite}},
+		{"drone", map[string]string{"DRONE": "true", "DRONE_BRANCH": "main"}, Provider{Name: Drone, Branch: "main"}},
+		{"drone pull request", map[string]string{"DRONE": "true", "DRONE_BRANCH": "main", "DRONE_PULL_REQUEST": "12", "DRONE_SOURCE_BRANCH": "feature/one", "DRONE_TARGET_BRANCH": "main"}, Provider{Name: Drone, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}}},
+		{"drone tag", map[string]string{"DRONE": "true", "DRONE_BRANCH": "main", "DRONE_TAG": "v1.0.0"}, Provider{Name: Drone}},
+		// the first provider detected wins
+		{"first provider", map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF_NAME": "main", "DRONE": "true", "DRONE_BRANCH": "other"}, Provider{Name: GitHubActions, Branch: "main"}},
+		{"trimmed", map[string]string{"CIRCLECI": "true", "CIRCLE_BRANCH": " main\n"}, Provider{Name: CircleCI, Branch: "main"}},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func(dir string) { tektonDir = dir }(tektonDir)
+			tektonDir = t.TempDir() + "/tekton"
+			assert.Equal(t, tc.expected, DetectWithEnv(func(key string) string { return tc.env[key] }))
+		})
+	}
+}
+
+func TestDetectGitHubPullRequestTarget(t *testing.T) {
+	// the event payload gives the number of the pull request as GITHUB_REF is the target branch
+	event := filepath.Join(t.TempDir(), "event.json")
+	assert.NoError(t, os.WriteFile(event, []byte(`{"action":"opened","number":12,"pull_request":{"number":12}}`), 0o644))
+	env := map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_EVENT_NAME": "pull_request_target", "GITHUB_EVENT_PATH": event,
+		"GITHUB_REF": "refs/heads/main", "GITHUB_REF_NAME": "main", "GITHUB_HEAD_REF": "feature/one", "GITHUB_BASE_REF": "main"}
+	assert.Equal(t, Provider{Name: GitHubActions, Branch: "feature/one", PullRequest: &PullRequest{Number: "12", SourceBranch: "feature/one", TargetBranch: "main"}},
+		DetectWithEnv(func(key string) string { return env[key] }))
+}
+
+func TestDetectTekton(t *testing.T) {
+	defer func(dir string) { tektonDir = dir }(tektonDir)
+	tektonDir = t.TempDir()
+	assert.Equal(t, Provider{Name: Tekton}, DetectWithEnv(func(string) string { return "" }))
+}
+
+func TestCurrentBranch(t *testing.T) {
+	assert := assert.New(t)
+	for _, it := range []string{"GITHUB_ACTIONS", "GITLAB_CI", "JENKINS_URL", "TF_BUILD", "CIRCLECI", "BITBUCKET_BUILD_NUMBER", "BUILDKITE", "DRONE"} {
+		t.Setenv(it, "")
+	}
+
+	t.Setenv(BranchEnv, "")
+	t.Setenv("CIRCLECI", "true")
+	t.Setenv("CIRCLE_BRANCH", "feature/one")
+	assert.Equal("feature/one", CurrentBranch())
+
+	// GIT_BRANCH takes precedence over the CI provider
+	t.Setenv(BranchEnv, "main")
+	assert.Equal("main", CurrentBranch())
+}
@@ -3,12 +3,12 @@ package git
 // Commit data
 type Commit struct {
 	// Hash of the commit object.
-	Hash Hash
+	Hash Hash `json:"hash"`
 	// Author is the original author of the commit.
-	Author Signature
+	Author Signature `json:"author"`
 	// Committer is the one performing the commit.
 	// It might be different from Author.
-	Committer Signature
+	Committer Signature `json:"committer"`
 	// Message is the commit message, contains arbitrary text.
-	Message string
+	Message string `json:"message"`
 }
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// BreakingChangeToken is the footer token of a breaking change
+	BreakingChangeToken = "BREAKING CHANGE"
+	// BreakingChangeTokenAlt is the synonym footer token of a breaking change
+	BreakingChangeTokenAlt = "BREAKING-CHANGE"
+)
+
+const (
+	// ConventionalHeaderPattern is the regular expression of a Conventional Commit header.
+	// See ParseCommitMessage for the named groups.
+	ConventionalHeaderPattern = `^(?P<type>[a-zA-Z][\w-]*)(?:\((?P<scope>[^()\r\n]*)\))?(?P<breaking>!)?: (?P<description>.*)$`
+)
+
+var (
+	/* const */ conventionalHeaderRegex = regexp.MustCompile(ConventionalHeaderPattern)
+	/* const */ conventionalFooterRegex = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[\w-]+)(: | #)(.*)$`)
+)
+
+// Footer is a trailer of a commit message such as "Refs: #123" or "BREAKING CHANGE: drop v1 API"
+type Footer struct {
+	// Token is the footer key. It is BREAKING CHANGE or a word without space.
+	Token string `json:"token"`
+	// Value is the footer value, it might contain several lines.
+	Value string `json:"value"`
+}
+
+// IsBreakingChange returns true if the footer declares a breaking change
+func (f Footer) IsBreakingChange() bool {
+	return f.Token == BreakingChangeToken || f.Token == BreakingChangeTokenAlt
+}
+
+// ConventionalCommit is the structured representation of a commit message following https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	// Type is the type of the commit such as feat or fix.
+	Type string `json:"type,omitempty"`
+	// Scope is the optional scope of the commit.
+	Scope string `json:"scope,omitempty"`
+	// Breaking is true if the commit has the ! marker or a BREAKING CHANGE footer.
+	Breaking bool `json:"breaking"`
+	// BreakingChange describes the breaking change.
+	// It is the value of the BREAKING CHANGE footer or the description if the commit only has the ! marker.
+	BreakingChange string `json:"breakingChange,omitempty"`
+	// Description is the short summary of the header.
+	// It is the whole header if the header does not follow the specification.
+	Description string `json:"description"`
+	// Body is the free-form text between the header and the footers.
+	Body string `json:"body,omitempty"`
+	// Footers are the trailers of the message in order of appearance.
+	Footers []Footer `json:"footers,omitempty"`
+	// Valid is true if the header follows the specification.
+	Valid bool `json:"valid"`
+}
+
+// Footer returns the value of the first footer with the token, or an empty string if there is none.
+// Tokens are case insensitive, except BREAKING CHANGE.
+func (c ConventionalCommit) Footer(token string) string {
+	breaking := Footer{Token: token}.IsBreakingChange()
+	for _, f := range c.Footers {
+		if f.Token == token || (!breaking && !f.IsBreakingChange() && strings.EqualFold(f.Token, token)) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Conventional parses the commit message as a Conventional Commit
+func (c Commit) Conventional() ConventionalCommit {
+	return ParseConventionalCommit(c.Message)
+}
+
+// ParseConventionalCommit parses a commit message as a Conventional Commit.
+// A malformed message does not fail: the result is not Valid and its Description is the whole header,
+// its body and footers are still parsed.
+func ParseConventionalCommit(message string) ConventionalCommit {
+	return ParseCommitMessage(message, conventionalHeaderRegex)
+}
+
+// ParseCommitMessage parses a commit message whose header follows another convention than Conventional Commits.
+// The header regular expression can define the named groups type, scope, breaking and description.
+// The header is valid if it matches and its description is not empty.
+// The body and footers are parsed as for a Conventional Commit.
+func ParseCommitMessage(message string, header *regexp.Regexp) ConventionalCommit {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(message), "\r\n", "\n"), "\n")
+
+	ret := parseHeader(strings.TrimSpace(lines[0]), header)
+
+	// the footers start at the first paragraph beginning with a footer token
+	rest := lines[1:]
+	footerStart := len(rest)
+	for i, line := range rest {
+		if conventionalFooterRegex.MatchString(line) && (i == 0 || isBlank(rest[i-1])) {
+			footerStart = i
+			break
+		}
+	}
+	ret.Body = strings.TrimSpace(strings.Join(rest[:footerStart], "\n"))
+	ret.Footers = parseFooters(rest[footerStart:])
+
+	for _, f := range ret.Footers {
+		if f.IsBreakingChange() {
+			ret.Breaking = true
+			if ret.BreakingChange == "" {
+				ret.BreakingChange = f.Value
+			}
+		}
+	}
+	if ret.Breaking && ret.BreakingChange == "" {
+		ret.BreakingChange = ret.Description
+	}
+	return ret
+}
+
+// parseHeader parses the header with the named groups of the regular expression
+func parseHeader(header string, regex *regexp.Regexp) ConventionalCommit {
+	ret := ConventionalCommit{Description: header}
+	m := regex.FindStringSubmatch(header)
+	if m == nil {
+		return ret
+	}
+	group := func(name string) string {
+		if i := regex.SubexpIndex(name); i > 0 {
+			return strings.TrimSpace(m[i])
+		}
+		return ""
+	}
+	if description := group("description"); description != "" {
+		ret.Type = group("type")
+		ret.Scope = group("scope")
+		ret.Breaking = group("breaking") != ""
+		ret.Description = description
+		ret.Valid = true
+	}
+	return ret
+}
+
+// parseFooters parses the footer lines, a line that does not start with a token continues the previous footer value
+func parseFooters(lines []string) []Footer {
+	var footers []Footer
+	for _, line := range lines {
+		if m := conventionalFooterRegex.FindStringSubmatch(line); m != nil {
+			value := m[3]
+			if m[2] == " #" {
+				value = "#" + value
+			}
+			footers = append(footers, Footer{Token: m[1], Value: value})
+		} else if len(footers) > 0 {
+			footers[len(footers)-1].Value += "\n" + line
+		}
+	}
+	for i := range footers {
+		footers[i].Value = strings.TrimSpace(footers[i].Value)
+	}
+	return footers
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
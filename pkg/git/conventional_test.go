@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	testData := []struct {
+		message  string
+		expected ConventionalCommit
+	}{
+		{
+			"feat: add parser",
+			ConventionalCommit{Type: "feat", Description: "add parser", Valid: true},
+		},
+		{
+			"fix(git): handle empty message\n",
+			ConventionalCommit{Type: "fix", Scope: "git", Description: "handle empty message", Valid: true},
+		},
+		{
+			"feat(api)!: drop v1",
+			ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, BreakingChange: "drop v1", Description: "drop v1", Valid: true},
+		},
+		{
+			"refactor: rename options\r\n\r\nFirst paragraph\r\nstill first.\r\n\r\nSecond paragraph.\r\n\r\nBREAKING-CHANGE: options are renamed\r\nReviewed-by: Z\r\nRefs #133",
+			ConventionalCommit{
+				Type:           "refactor",
+				Breaking:       true,
+				BreakingChange: "options are renamed",
+				Description:    "rename options",
+				Body:           "First paragraph\nstill first.\n\nSecond paragraph.",
+				Footers:        []Footer{{"BREAKING-CHANGE", "options are renamed"}, {"Reviewed-by", "Z"}, {"Refs", "#133"}},
+				Valid:          true,
+			},
+		},
+		{
+			"feat: allow config\n\nBREAKING CHANGE: the config key\nhas been renamed\nSigned-off-by: Jane <jane@example.com>",
+			ConventionalCommit{
+				Type:           "feat",
+				Breaking:       true,
+				BreakingChange: "the config key\nhas been renamed",
+				Description:    "allow config",
+				Footers:        []Footer{{"BREAKING CHANGE", "the config key\nhas been renamed"}, {"Signed-off-by", "Jane <jane@example.com>"}},
+				Valid:          true,
+			},
+		},
+		{
+			"update README.md\n\nwith a body",
+			ConventionalCommit{Description: "update README.md", Body: "with a body"},
+		},
+		{
+			"feat:missing space",
+			ConventionalCommit{Description: "feat:missing space"},
+		},
+		{
+			"feat(scope: unclosed scope",
+			ConventionalCommit{Description: "feat(scope: unclosed scope"},
+		},
+		{
+			"feat: ",
+			ConventionalCommit{Description: "feat:"},
+		},
+		{
+			"",
+			ConventionalCommit{},
+		},
+		{
+			"Merge branch 'feature'\n\nBREAKING CHANGE: still detected",
+			ConventionalCommit{
+				Breaking:       true,
+				BreakingChange: "still detected",
+				Description:    "Merge branch 'feature'",
+				Footers:        []Footer{{"BREAKING CHANGE", "still detected"}},
+			},
+		},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseConventionalCommit(tc.message))
+		})
+	}
+}
+
+func TestConventionalCommitFooter(t *testing.T) {
+	assert := assert.New(t)
+
+	c := Commit{Message: "fix: typo\n\nRefs: #1\nBreaking-Change: not a breaking change"}.Conventional()
+
+	assert.Equal("#1", c.Footer("refs"))
+	assert.Equal("", c.Footer("Closes"))
+	assert.Equal("not a breaking change", c.Footer("breaking-change"))
+	assert.Equal("", c.Footer(BreakingChangeTokenAlt))
+	assert.False(c.Breaking)
+}
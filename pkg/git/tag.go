@@ -3,11 +3,11 @@ package git
 // Tag is data of git-tag
 type Tag struct {
 	// Hash of the tag.
-	Hash Hash
+	Hash Hash `json:"hash,omitempty"`
 	// Name of the tag.
-	Name string
+	Name string `json:"name"`
 	// Tagger is the one who created the tag.
-	Tagger Signature
+	Tagger Signature `json:"tagger"`
 	// Message is an arbitrary text message.
-	Message string
+	Message string `json:"message,omitempty"`
 }
@@ -0,0 +1,188 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// AuditNotSemver is the type of violation for a tag that is not a semver version
+	AuditNotSemver = "not-semver"
+	// AuditSkippedVersion is the type of violation for a tag that does not directly follow its previous tag
+	AuditSkippedVersion = "skipped-version"
+	// AuditUnderBump is the type of violation for a tag that bumps less than its commits require
+	AuditUnderBump = "under-bump"
+	// AuditOverBump is the type of violation for a tag that bumps more than its commits require
+	AuditOverBump = "over-bump"
+)
+
+// AuditViolation represents a tag that does not follow the commit conventions
+type AuditViolation struct {
+	// Tag is the name of the tag in violation
+	Tag string `json:"tag"`
+	// PreviousTag is the name of the previous tag the tag has been checked against
+	PreviousTag string `json:"previousTag,omitempty"`
+	// Type is the type of violation: not-semver, skipped-version, under-bump or over-bump
+	Type string `json:"type"`
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// String returns a string representation of an AuditViolation
+func (v AuditViolation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Tag, v.Type, v.Message)
+}
+
+// Audit checks each release tag of the repository against the commits between it and its previous release tag.
+// Pre-release tags are only checked to be semver compatible.
+func (o *BumpStrategy) Audit() ([]AuditViolation, error) {
+	log.Debug("BumpStrategy: audit with configuration: %#v", o)
+
+	// Make sure we have the tags
+	if err := o.fetchTags(); err != nil {
+		return nil, err
+	}
+
+	tags, err := o.gitRepo.GetTags()
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get tags")
+	}
+	sortTags(tags)
+
+	violations := []AuditViolation{}
+	for _, tag := range tags {
+		v, err := o.auditTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			violations = append(violations, *v)
+		}
+	}
+	return violations, nil
+}
+
+func (o *BumpStrategy) auditTag(tag git.Tag) (*AuditViolation, error) {
+	next, err := NewVersion(extractVersionFromTag(tag.Name))
+	if err != nil {
+		return &AuditViolation{Tag: tag.Name, Type: AuditNotSemver, Message: err.Error()}, nil
+	}
+	if next.IsPreRelease() {
+		log.Debug("BumpStrategy: skip audit of pre-release %s", tag.Name)
+		return nil, nil
+	}
+
+	previousTag, previous, ok, err := o.previousRelease(tag.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Debug("BumpStrategy: skip audit of %s as its previous tag %q is not semver compatible", tag.Name, previousTag.Name)
+		return nil, nil
+	}
+
+	actual, ok := bumpStrategyTypeBetween(previous, next)
+	if !ok {
+		log.Debug("BumpStrategy: skip audit of %s as it has the same version as %s", tag.Name, previousTag.Name)
+		return nil, nil
+	}
+
+	newViolation := func(violationType string, format string, args ...interface{}) (*AuditViolation, error) {
+		return &AuditViolation{Tag: tag.Name, PreviousTag: previousTag.Name, Type: violationType, Message: fmt.Sprintf(format, args...)}, nil
+	}
+
+	// moving from an initial development release to 1.0.0 is a deliberate decision
+	stabilization := previous.IsUnstable() && next.String() == "1.0.0"
+
+	commits, err := o.gitRepo.GetCommits(previousTag.Name, tag.Name)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get commits of %s", tag.Name)
+	}
+	commits = o.excludeReleaseCommits(commits)
+
+	// a version forced by a Release-As footer is deliberate
+	context := o.newContext("", &previous, &previousTag, commits)
+	if releaseAs, err := o.computeReleaseAs(context); err == nil && releaseAs != nil && releaseAs.Version.Compare(next) == 0 {
+		log.Debug("BumpStrategy: skip audit of %s as it is forced by a %s footer", tag.Name, ReleaseAsToken)
+		return nil, nil
+	}
+
+	if expected := strategyVersionBumperMap[actual](previous); expected.Compare(next) != 0 && !stabilization {
+		return newViolation(AuditSkippedVersion, "%v does not directly follow %v, expected %v", next, previous, expected)
+	}
+	if len(commits) == 0 {
+		return newViolation(AuditOverBump, "bumps %s but there is no commit since %v", actual, previous)
+	}
+
+	required, ok := o.computeBumpStrategyType(context)
+	switch {
+	case !ok:
+		return newViolation(AuditOverBump, "bumps %s but its commits do not require any bump", actual)
+	case required > actual:
+		return newViolation(AuditUnderBump, "bumps %s but its commits require %s", actual, required)
+	case required < actual && !stabilization:
+		return newViolation(AuditOverBump, "bumps %s but its commits only require %s", actual, required)
+	}
+	return nil, nil
+}
+
+// previousRelease returns the closest release tag below the given tag, looking back through the pre-release tags.
+// It returns false if this tag is not semver compatible.
+func (o *BumpStrategy) previousRelease(tagName string) (git.Tag, Version, bool, error) {
+	for {
+		previousTag, err := o.gitRepo.GetLastRelativeTag(tagName + "^")
+		if errors.Is(err, ErrShallowClone) {
+			return previousTag, zeroVersion, false, newErrorC(err, "Cannot get previous tag of %s", tagName)
+		}
+		if err != nil {
+			// this is the first release
+			log.Debug("%v", newErrorC(err, "Unable to get previous tag of %s", tagName))
+			previousTag = git.Tag{}
+		}
+		previous, err := NewVersion(extractVersionFromTag(previousTag.Name))
+		if err != nil {
+			return previousTag, previous, false, nil
+		}
+		if !previous.IsPreRelease() {
+			return previousTag, previous, true, nil
+		}
+		tagName = previousTag.Name
+	}
+}
+
+// bumpStrategyTypeBetween returns the part of the version that has been bumped between 2 versions.
+// It returns false if they have the same major, minor and patch numbers.
+func bumpStrategyTypeBetween(previous Version, next Version) (BumpStrategyType, bool) {
+	switch {
+	case previous.Major != next.Major:
+		return MAJOR, true
+	case previous.Minor != next.Minor:
+		return MINOR, true
+	case previous.Patch != next.Patch:
+		return PATCH, true
+	default:
+		return PATCH, false
+	}
+}
+
+// sortTags sorts tags by version and then by name, tags that are not semver compatible come last
+func sortTags(tags []git.Tag) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		vi, errI := NewVersion(extractVersionFromTag(tags[i].Name))
+		vj, errJ := NewVersion(extractVersionFromTag(tags[j].Name))
+		switch {
+		case errI != nil && errJ != nil:
+			return tags[i].Name < tags[j].Name
+		case errI != nil || errJ != nil:
+			return errJ != nil
+		case vi.Compare(vj) != 0:
+			return vi.Compare(vj) < 0
+		default:
+			return tags[i].Name < tags[j].Name
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestAudit(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	commit := func(message string) []git.Commit {
+		return []git.Commit{{Hash: git.Hash("1234567890"), Message: message}}
+	}
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetTags().Times(1).Return([]git.Tag{
+		{Name: "latest"}, {Name: "v1.3.1"}, {Name: "v0.1.0"}, {Name: "v0.1.1"}, {Name: "v0.2.0"}, {Name: "v1.0.0"},
+		{Name: "v1.2.0"}, {Name: "v1.3.0"}, {Name: "v2.0.0-rc.0"}, {Name: "v1.4.0"}, {Name: "v1.4.1"},
+		{Name: "v1.5.0-rc.1"}, {Name: "v1.5.0"},
+	}, nil)
+	// first release
+	gitRepo.EXPECT().GetLastRelativeTag("v0.1.0^").Times(1).Return(git.Tag{}, newError("no tag"))
+	gitRepo.EXPECT().GetCommits("", "v0.1.0").Times(1).Return(commit("feat: init"), nil)
+	// valid patch
+	gitRepo.EXPECT().GetLastRelativeTag("v0.1.1^").Times(1).Return(git.Tag{Name: "v0.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.0", "v0.1.1").Times(1).Return(commit("fix: typo"), nil)
+	// breaking change on initial development release
+	gitRepo.EXPECT().GetLastRelativeTag("v0.2.0^").Times(1).Return(git.Tag{Name: "v0.1.1"}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.1", "v0.2.0").Times(1).Return(commit("feat!: breaking"), nil)
+	// stabilization on the same commit as v0.2.0
+	gitRepo.EXPECT().GetLastRelativeTag("v1.0.0^").Times(1).Return(git.Tag{Name: "v0.1.1"}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.1", "v1.0.0").Times(1).Return(commit("feat!: breaking"), nil)
+	// skipped 1.1.0
+	gitRepo.EXPECT().GetLastRelativeTag("v1.2.0^").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "v1.2.0").Times(1).Return(commit("feat: new"), nil)
+	// over-bump
+	gitRepo.EXPECT().GetLastRelativeTag("v1.3.0^").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "v1.3.0").Times(1).Return(commit("fix: typo"), nil)
+	// under-bump
+	gitRepo.EXPECT().GetLastRelativeTag("v1.3.1^").Times(1).Return(git.Tag{Name: "v1.3.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.3.0", "v1.3.1").Times(1).Return(commit("fix: typo\n\nBREAKING CHANGE: oops"), nil)
+	// over-bump without commit
+	gitRepo.EXPECT().GetLastRelativeTag("v1.4.0^").Times(1).Return(git.Tag{Name: "v1.3.1"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.3.1", "v1.4.0").Times(1).Return([]git.Commit{}, nil)
+	// previous tag is a pre-release of another version
+	gitRepo.EXPECT().GetLastRelativeTag("v1.4.1^").Times(1).Return(git.Tag{Name: "v2.0.0-rc.0"}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v2.0.0-rc.0^").Times(1).Return(git.Tag{Name: "v1.4.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.4.0", "v1.4.1").Times(1).Return(commit("fix: typo"), nil)
+	// previous tag is a pre-release of the same version
+	gitRepo.EXPECT().GetLastRelativeTag("v1.5.0^").Times(1).Return(git.Tag{Name: "v1.5.0-rc.1"}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.5.0-rc.1^").Times(1).Return(git.Tag{Name: "v1.4.1"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.4.1", "v1.5.0").Times(1).Return(commit("fix: typo"), nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	violations, err := strategy.Audit()
+
+	assert.NoError(err)
+	assert.Equal([]AuditViolation{
+		{Tag: "v1.2.0", PreviousTag: "v1.0.0", Type: AuditSkippedVersion, Message: "1.2.0 does not directly follow 1.0.0, expected 1.1.0"},
+		{Tag: "v1.3.0", PreviousTag: "v1.2.0", Type: AuditOverBump, Message: "bumps MINOR but its commits only require PATCH"},
+		{Tag: "v1.3.1", PreviousTag: "v1.3.0", Type: AuditUnderBump, Message: "bumps PATCH but its commits require MAJOR"},
+		{Tag: "v1.4.0", PreviousTag: "v1.3.1", Type: AuditOverBump, Message: "bumps MINOR but there is no commit since 1.3.1"},
+		{Tag: "v1.5.0", PreviousTag: "v1.4.1", Type: AuditOverBump, Message: "bumps MINOR but its commits only require PATCH"},
+		{Tag: "latest", Type: AuditNotSemver, Message: "'latest' is not a semver compatible version"},
+	}, violations)
+}
+
+func TestAuditWithIgnoredScope(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetTags().Times(1).Return([]git.Tag{{Name: "v1.0.1"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.0.1^").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "v1.0.1").Times(1).Return([]git.Commit{{Hash: git.Hash("1234567890"), Message: "fix(deps): bump dependencies"}}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.Scopes = ScopeRules{Ignore: []string{"deps"}}
+	violations, err := strategy.Audit()
+
+	assert.NoError(err)
+	assert.Equal([]AuditViolation{
+		{Tag: "v1.0.1", PreviousTag: "v1.0.0", Type: AuditOverBump, Message: "bumps PATCH but its commits do not require any bump"},
+	}, violations)
+}
+
+func ExampleAuditViolation_String() {
+	v := AuditViolation{Tag: "v1.3.1", PreviousTag: "v1.3.0", Type: AuditUnderBump, Message: "bumps PATCH but its commits require MAJOR"}
+	fmt.Println(v)
+	// Output: v1.3.1: under-bump: bumps PATCH but its commits require MAJOR
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/arnaud-deprez/gsemver/internal/utils"
 )
@@ -49,6 +50,12 @@ type BumpBranchesStrategy struct {
 	Strategy BumpStrategyType `json:"strategy"`
 	// BranchesPattern is the regex used to match against the current branch
 	BranchesPattern *regexp.Regexp `json:"branchesPattern,omitempty"`
+	// PullRequest restricts the strategy to the builds of pull requests if true, or to the other builds if false.
+	// If nil, the strategy matches both.
+	PullRequest *bool `json:"pullRequest,omitempty"`
+	// TargetBranchesPattern is the regex used to match against the target branch of the pull request.
+	// If set, the strategy only matches the builds of pull requests.
+	TargetBranchesPattern *regexp.Regexp `json:"targetBranchesPattern,omitempty"`
 	// PreRelease defines if the bump strategy should generate a pre-release version
 	PreRelease bool `json:"preRelease"`
 	// PreReleaseTemplate defines the pre-release template for the next version
@@ -61,6 +68,37 @@ type BumpBranchesStrategy struct {
 	// BuildMetadataTemplate defines the build metadata for the next version.
 	// It can be a static value but it will usually be a go-template expression to guarantee uniqueness of each built version.
 	BuildMetadataTemplate *template.Template `json:"buildMetadataTemplate,omitempty"`
+	// Command is the external command used by the EXEC strategy to compute the bump.
+	// It receives the Context serialized in json on its standard input and must write an ExecResponse in json on its standard output.
+	Command string `json:"command,omitempty"`
+	// CommandTimeout is the maximum duration allowed for Command to complete. DefaultCommandTimeout is used if not set.
+	CommandTimeout time.Duration `json:"commandTimeout,omitempty"`
+	// DirtyPolicy defines how the version is computed when the working tree has uncommitted changes.
+	// By default, they are ignored.
+	DirtyPolicy DirtyPolicy `json:"dirtyPolicy,omitempty"`
+}
+
+// NewExecBumpBranchesStrategy creates a new BumpBranchesStrategy that delegates the bump decision to an external command.
+func NewExecBumpBranchesStrategy(pattern string, command string, timeout time.Duration) *BumpBranchesStrategy {
+	s := NewBumpBranchesStrategy(EXEC, pattern, false, "", false, "")
+	s.Command = command
+	s.CommandTimeout = timeout
+	return s
+}
+
+// matches checks if the strategy applies to the branch and the pull request of the context
+func (s *BumpBranchesStrategy) matches(ctx *Context) bool {
+	if !s.BranchesPattern.MatchString(ctx.Branch) {
+		return false
+	}
+	pullRequest := ctx.CI.PullRequest
+	if s.PullRequest != nil && *s.PullRequest != (pullRequest != nil) {
+		return false
+	}
+	if s.TargetBranchesPattern != nil && (pullRequest == nil || !s.TargetBranchesPattern.MatchString(pullRequest.TargetBranch)) {
+		return false
+	}
+	return true
 }
 
 // createVersionBumperFrom is an implementation for BumpBranchStrategy
@@ -83,8 +121,16 @@ func (s BumpBranchesStrategy) GoString() string {
 	sb.WriteString("version.BumpBranchesStrategy{")
 	sb.WriteString(fmt.Sprintf("Strategy: %v, ", s.Strategy))
 	sb.WriteString(fmt.Sprintf("BranchesPattern: &regexp.Regexp{expr: %q}, ", s.BranchesPattern))
+	if s.PullRequest != nil {
+		sb.WriteString(fmt.Sprintf("PullRequest: %v, ", *s.PullRequest))
+	} else {
+		sb.WriteString("PullRequest: nil, ")
+	}
+	sb.WriteString(fmt.Sprintf("TargetBranchesPattern: &regexp.Regexp{expr: %q}, ", utils.RegexpToString(s.TargetBranchesPattern)))
 	sb.WriteString(fmt.Sprintf("PreRelease: %v, PreReleaseTemplate: &template.Template{text: %q}, PreReleaseOverwrite: %v, ", s.PreRelease, utils.TemplateToString(s.PreReleaseTemplate), s.PreReleaseOverwrite))
-	sb.WriteString(fmt.Sprintf("BuildMetadataTemplate: &template.Template{text: %q}", utils.TemplateToString(s.BuildMetadataTemplate)))
+	sb.WriteString(fmt.Sprintf("BuildMetadataTemplate: &template.Template{text: %q}, ", utils.TemplateToString(s.BuildMetadataTemplate)))
+	sb.WriteString(fmt.Sprintf("Command: %q, CommandTimeout: %v, ", s.Command, s.CommandTimeout))
+	sb.WriteString(fmt.Sprintf("DirtyPolicy: %v", s.DirtyPolicy))
 	sb.WriteString("}")
 	return sb.String()
 }
@@ -94,13 +140,17 @@ func (s *BumpBranchesStrategy) MarshalJSON() ([]byte, error) {
 	type Alias BumpBranchesStrategy
 	return json.Marshal(&struct {
 		BranchesPattern       string `json:"branchesPattern,omitempty"`
+		TargetBranchesPattern string `json:"targetBranchesPattern,omitempty"`
 		PreReleaseTemplate    string `json:"preReleaseTemplate,omitempty"`
 		BuildMetadataTemplate string `json:"buildMetadataTemplate,omitempty"`
+		CommandTimeout        string `json:"commandTimeout,omitempty"`
 		*Alias
 	}{
 		BranchesPattern:       utils.RegexpToString(s.BranchesPattern),
+		TargetBranchesPattern: utils.RegexpToString(s.TargetBranchesPattern),
 		PreReleaseTemplate:    utils.TemplateToString(s.PreReleaseTemplate),
 		BuildMetadataTemplate: utils.TemplateToString(s.BuildMetadataTemplate),
+		CommandTimeout:        utils.DurationToString(s.CommandTimeout),
 		Alias:                 (*Alias)(s),
 	})
 }
@@ -110,8 +160,10 @@ func (s *BumpBranchesStrategy) UnmarshalJSON(data []byte) error {
 	type Alias BumpBranchesStrategy
 	aux := struct {
 		BranchesPattern       string `json:"branchesPattern,omitempty"`
+		TargetBranchesPattern string `json:"targetBranchesPattern,omitempty"`
 		PreReleaseTemplate    string `json:"preReleaseTemplate,omitempty"`
 		BuildMetadataTemplate string `json:"buildMetadataTemplate,omitempty"`
+		CommandTimeout        string `json:"commandTimeout,omitempty"`
 		*Alias
 	}{
 		Alias: (*Alias)(s),
@@ -119,8 +171,17 @@ func (s *BumpBranchesStrategy) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
+	timeout, err := utils.ParseDuration(aux.CommandTimeout)
+	if err != nil {
+		return err
+	}
 	s.BranchesPattern = regexp.MustCompile(aux.BranchesPattern)
+	s.TargetBranchesPattern = nil
+	if aux.TargetBranchesPattern != "" {
+		s.TargetBranchesPattern = regexp.MustCompile(aux.TargetBranchesPattern)
+	}
 	s.PreReleaseTemplate = utils.NewTemplate(aux.PreReleaseTemplate)
 	s.BuildMetadataTemplate = utils.NewTemplate(aux.BuildMetadataTemplate)
+	s.CommandTimeout = timeout
 	return nil
 }
@@ -3,9 +3,14 @@ package version
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
 )
 
 func TestBumpBranchesStrategyEncodingJson(t *testing.T) {
@@ -27,6 +32,14 @@ func TestBumpBranchesStrategyEncodingJson(t *testing.T) {
 			`{"branchesPattern":".*","preRelease":true,"preReleaseOverwrite":true,"buildMetadataTemplate":"{{.Branch}}.{{.Commits | len}}","strategy":"AUTO"}`,
 			NewBumpAllBranchesStrategy(AUTO, true, "", true, "{{.Branch}}.{{.Commits | len}}"),
 		},
+		{
+			`{"branchesPattern":".*","preRelease":false,"preReleaseOverwrite":false,"command":"./plugin.sh","commandTimeout":"10s","strategy":"EXEC"}`,
+			NewExecBumpBranchesStrategy(".*", "./plugin.sh", 10*time.Second),
+		},
+		{
+			`{"branchesPattern":".*","pullRequest":true,"targetBranchesPattern":"^main$","preRelease":true,"preReleaseTemplate":"pr.{{.CI.PullRequest.Number}}","preReleaseOverwrite":false,"strategy":"AUTO"}`,
+			newPullRequestBumpBranchesStrategy(true, "^main$"),
+		},
 	}
 
 	for idx, tc := range testData {
@@ -47,7 +60,11 @@ func TestBumpBranchesStrategyEncodingJson(t *testing.T) {
 			if tc.objVal.PreReleaseTemplate != nil {
 				assert.Equal(tc.objVal.PreReleaseTemplate.Root.String(), out.PreReleaseTemplate.Root.String())
 			}
+			assert.Equal(tc.objVal.PullRequest, out.PullRequest)
+			assert.Equal(utils.RegexpToString(tc.objVal.TargetBranchesPattern), utils.RegexpToString(out.TargetBranchesPattern))
 			assert.Equal(tc.objVal.PreReleaseOverwrite, out.PreReleaseOverwrite)
+			assert.Equal(tc.objVal.Command, out.Command)
+			assert.Equal(tc.objVal.CommandTimeout, out.CommandTimeout)
 			if tc.objVal.BuildMetadataTemplate != nil {
 				assert.Equal(tc.objVal.BuildMetadataTemplate.Root.String(), out.BuildMetadataTemplate.Root.String())
 			}
@@ -55,8 +72,51 @@ func TestBumpBranchesStrategyEncodingJson(t *testing.T) {
 	}
 }
 
+// newPullRequestBumpBranchesStrategy creates a pre-release strategy for the pull requests targeting a branch
+func newPullRequestBumpBranchesStrategy(pullRequest bool, targetBranchesPattern string) *BumpBranchesStrategy {
+	s := NewPreReleaseBumpBranchesStrategy(".*", "pr.{{.CI.PullRequest.Number}}", false)
+	s.PullRequest = &pullRequest
+	if targetBranchesPattern != "" {
+		s.TargetBranchesPattern = regexp.MustCompile(targetBranchesPattern)
+	}
+	return s
+}
+
+func TestBumpBranchesStrategyMatches(t *testing.T) {
+	pullRequest := &ci.PullRequest{Number: "42", SourceBranch: "feature/one", TargetBranch: "main"}
+	notPullRequest := NewDefaultBumpBranchesStrategy(".*")
+	notPullRequest.PullRequest = new(bool)
+	targetOnly := NewDefaultBumpBranchesStrategy(".*")
+	targetOnly.TargetBranchesPattern = regexp.MustCompile("^release/.*$")
+
+	testData := []struct {
+		name        string
+		strategy    *BumpBranchesStrategy
+		branch      string
+		pullRequest *ci.PullRequest
+		expected    bool
+	}{
+		{"any build", NewDefaultBumpBranchesStrategy("^feature/.*$"), "feature/one", pullRequest, true},
+		{"any build branch", NewDefaultBumpBranchesStrategy("^main$"), "feature/one", pullRequest, false},
+		{"pull request", newPullRequestBumpBranchesStrategy(true, ""), "feature/one", pullRequest, true},
+		{"pull request without pull request", newPullRequestBumpBranchesStrategy(true, ""), "feature/one", nil, false},
+		{"not pull request", notPullRequest, "main", nil, true},
+		{"not pull request with pull request", notPullRequest, "feature/one", pullRequest, false},
+		{"target branch", newPullRequestBumpBranchesStrategy(true, "^main$"), "feature/one", pullRequest, true},
+		{"other target branch", newPullRequestBumpBranchesStrategy(true, "^release/.*$"), "feature/one", pullRequest, false},
+		{"target branch without pull request", targetOnly, "release/1.x", nil, false},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &Context{Branch: tc.branch, CI: ci.Provider{Name: ci.GitHubActions, Branch: tc.branch, PullRequest: tc.pullRequest}}
+			assert.Equal(t, tc.expected, tc.strategy.matches(ctx))
+		})
+	}
+}
+
 func ExampleBumpBranchesStrategy_GoString() {
 	s := NewBumpBranchesStrategy(AUTO, ".*", true, "foo", true, "bar")
 	fmt.Printf("%#v\n", s)
-	// Output: version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: ".*"}, PreRelease: true, PreReleaseTemplate: &template.Template{text: "foo"}, PreReleaseOverwrite: true, BuildMetadataTemplate: &template.Template{text: "bar"}}
+	// Output: version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: ".*"}, PullRequest: nil, TargetBranchesPattern: &regexp.Regexp{expr: ""}, PreRelease: true, PreReleaseTemplate: &template.Template{text: "foo"}, PreReleaseOverwrite: true, BuildMetadataTemplate: &template.Template{text: "bar"}, Command: "", CommandTimeout: 0s, DirtyPolicy: ignore}
 }
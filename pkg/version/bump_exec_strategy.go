@@ -0,0 +1,93 @@
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	shellquote "github.com/kballard/go-shellquote"
+
+	"github.com/arnaud-deprez/gsemver/internal/command"
+	"github.com/arnaud-deprez/gsemver/internal/log"
+)
+
+// DefaultCommandTimeout defines the default timeout of the command used by the EXEC strategy
+const DefaultCommandTimeout = 30 * time.Second
+
+// ExecResponse is the json response an EXEC strategy command must write on its standard output.
+// Either Bump or Version must be set.
+type ExecResponse struct {
+	// Bump is the part of the version to bump: major, minor, patch or none
+	Bump string `json:"bump,omitempty"`
+	// Version is an explicit version to use as the next version
+	Version string `json:"version,omitempty"`
+}
+
+// computeExecVersionBumper runs the strategy command with the serialized context on its standard input
+// and creates a versionBumper from its response.
+func (s *BumpBranchesStrategy) computeExecVersionBumper(ctx *Context) (versionBumper, error) {
+	args, err := shellquote.Split(s.Command)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot parse command %q", s.Command)
+	}
+	if len(args) == 0 {
+		return nil, newError("EXEC strategy for branches %q requires a command", s.BranchesPattern)
+	}
+
+	in, err := json.Marshal(ctx)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot serialize context for command %q", s.Command)
+	}
+
+	timeout := s.CommandTimeout
+	if timeout == 0 {
+		timeout = DefaultCommandTimeout
+	}
+
+	var out, errOut bytes.Buffer
+	cmd := command.NewWithVarArgs(args...).WithTimeout(timeout)
+	cmd.In = bytes.NewReader(in)
+	cmd.Out = &out
+	cmd.Err = &errOut
+	if _, err := cmd.Run(); err != nil {
+		return nil, newErrorC(err, "Command %q failed with stderr: %q", s.Command, strings.TrimSpace(errOut.String()))
+	}
+
+	var res ExecResponse
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		return nil, newErrorC(err, "Command %q returned an invalid response: %q", s.Command, strings.TrimSpace(out.String()))
+	}
+	log.Debug("BumpStrategy: command %q responded with %#v", s.Command, res)
+
+	return s.createVersionBumperFromExecResponse(&res, ctx)
+}
+
+func (s *BumpBranchesStrategy) createVersionBumperFromExecResponse(res *ExecResponse, ctx *Context) (versionBumper, error) {
+	if res.Version != "" {
+		if res.Bump != "" {
+			return nil, newError("Command %q response cannot contain both bump and version", s.Command)
+		}
+		next, err := NewVersion(res.Version)
+		if err != nil {
+			return nil, newErrorC(err, "Command %q returned an invalid version", s.Command)
+		}
+		if ctx.LastVersion != nil && !next.GreaterThan(*ctx.LastVersion) {
+			return nil, newError("Command %q returned a version %v which is not greater than the last version %v", s.Command, next, ctx.LastVersion)
+		}
+		return s.createVersionBumperFrom(func(Version) Version { return next }, ctx), nil
+	}
+
+	switch strings.ToLower(res.Bump) {
+	case "major":
+		return s.createVersionBumperFrom(Version.BumpMajor, ctx), nil
+	case "minor":
+		return s.createVersionBumperFrom(Version.BumpMinor, ctx), nil
+	case "patch":
+		return s.createVersionBumperFrom(Version.BumpPatch, ctx), nil
+	case "none":
+		return versionBumperIdentity, nil
+	default:
+		return nil, newError("Command %q returned an unknown bump %q, expected major, minor, patch or none", s.Command, res.Bump)
+	}
+}
@@ -0,0 +1,123 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+// writeScript writes a shell script standing in for a bump plugin and returns its path
+func writeScript(t *testing.T, content string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+	script := filepath.Join(t.TempDir(), "plugin.sh")
+	assert.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"+content+"\n"), 0755))
+	return script
+}
+
+func TestBumpVersionStrategyExec(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		script                string
+		buildMetadataTemplate string
+		expected              string
+	}{
+		{`cat > /dev/null; echo '{"bump":"major"}'`, "", "2.0.0"},
+		{`cat > /dev/null; echo '{"bump":"MINOR"}'`, "", "1.2.0"},
+		{`cat > /dev/null; echo '{"bump":"patch"}'`, "", "1.1.1"},
+		{`cat > /dev/null; echo '{"bump":"none"}'`, "", "1.1.0"},
+		{`cat > /dev/null; echo '{"version":"3.0.0"}'`, "", "3.0.0"},
+		{`cat > /dev/null; echo '{"bump":"minor"}'`, "{{.Branch}}", "1.1.0+main"},
+		// the plugin receives the serialized context
+		{`in=$(cat); echo "$in" | grep -q '"branch":"main"' && echo "$in" | grep -q '"major":1' && echo '{"bump":"minor"}'`, "", "1.2.0"},
+		{`grep -q '"message":"feat: plugin"' && echo '{"bump":"minor"}'`, "", "1.2.0"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(t *testing.T) {
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			from := "v1.1.0"
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: from}, nil)
+			gitRepo.EXPECT().GetCommits(from, "HEAD").Times(1).Return([]git.Commit{
+				{
+					Author:    git.Signature{Name: "Arnaud Deprez", Email: "xxx@example.com"},
+					Committer: git.Signature{Name: "Arnaud Deprez", Email: "xxx@example.com"},
+					Hash:      git.Hash("1234567890"),
+					Message:   `feat: plugin`,
+				},
+			}, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+			s := NewExecBumpBranchesStrategy(".*", writeScript(t, tc.script), 0)
+			s.BuildMetadataTemplate = NewBuildBumpBranchesStrategy(".*", tc.buildMetadataTemplate).BuildMetadataTemplate
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.BumpStrategies = []BumpBranchesStrategy{*s}
+			version, err := strategy.Bump()
+
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
+
+func TestBumpVersionStrategyExecErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		script   string
+		timeout  time.Duration
+		expected string
+	}{
+		{`echo 'plugin failure' >&2; exit 1`, 0, "plugin failure"},
+		{`echo 'not json'`, 0, "invalid response"},
+		{`echo '{"bump":"huge"}'`, 0, "unknown bump"},
+		{`echo '{"version":"foo"}'`, 0, "invalid version"},
+		{`echo '{"bump":"major","version":"2.0.0"}'`, 0, "cannot contain both"},
+		{`echo '{"version":"1.0.0"}'`, 0, "returned a version 1.0.0 which is not greater than the last version 1.0.0"},
+		{`echo '{"version":"0.9.0"}'`, 0, "returned a version 0.9.0 which is not greater than the last version 1.0.0"},
+		{`exec sleep 5`, 100 * time.Millisecond, "timed out"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(t *testing.T) {
+			s := NewExecBumpBranchesStrategy(".*", writeScript(t, tc.script), tc.timeout)
+			ctx := NewContext("main", &Version{Major: 1}, &git.Tag{Name: "v1.0.0"}, []git.Commit{})
+			_, err := s.computeExecVersionBumper(ctx)
+
+			assert.Error(err)
+			assert.Contains(err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestBumpVersionStrategyExecWithoutCommand(t *testing.T) {
+	s := NewExecBumpBranchesStrategy(".*", "", 0)
+	_, err := s.computeExecVersionBumper(NewContext("main", &Version{}, &git.Tag{}, nil))
+	assert.EqualError(t, err, `EXEC strategy for branches ".*" requires a command`)
+}
+
+func TestBumpVersionStrategyExecInvalidCommand(t *testing.T) {
+	s := NewExecBumpBranchesStrategy(".*", "./plugin.sh 'unterminated", 0)
+	_, err := s.computeExecVersionBumper(NewContext("main", &Version{}, &git.Tag{}, nil))
+	assert.EqualError(t, err, `Cannot parse command "./plugin.sh 'unterminated" caused by: Unterminated single-quoted string`)
+
+	s = NewExecBumpBranchesStrategy(".*", "  ", 0)
+	_, err = s.computeExecVersionBumper(NewContext("main", &Version{}, &git.Tag{}, nil))
+	assert.EqualError(t, err, `EXEC strategy for branches ".*" requires a command`)
+}
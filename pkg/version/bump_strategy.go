@@ -1,11 +1,15 @@
 package version
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
 )
 
 const (
@@ -13,6 +17,8 @@ const (
 	DefaultMajorPattern = `(?:^.+\!:.+|(?m)^BREAKING CHANGE:.+$)`
 	// DefaultMinorPattern defines default regular expression to match a commit message with a minor change.
 	DefaultMinorPattern = `^(?:feat|chore|build|ci|refactor|perf)(?:\(.+\))?:.+`
+	// DefaultCommitPattern defines default regular expression a commit header must match to follow the conventions.
+	DefaultCommitPattern = `^(?:build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(?:\(.+\))?!?: .+`
 	// DefaultReleaseBranchesPattern defines default regular expression to match release branches
 	DefaultReleaseBranchesPattern = `^(main|master|release/.*)$`
 	// DefaultPreRelease defines default pre-release activation for non release branches
@@ -40,16 +46,46 @@ type versionBumper func(Version) Version
 
 // BumpStrategy allows you to configure the bump strategy
 type BumpStrategy struct {
+	// Convention is the name of the convention used to parse the commit messages, see GetConvention
+	Convention string `json:"convention,omitempty"`
 	// MajorPattern is the regex used to detect if a commit contains a breaking/major change
+	// A valid commit with a ! marker or a BREAKING CHANGE/BREAKING-CHANGE footer is always a breaking change.
 	// See RegexMinor for more details
 	MajorPattern *regexp.Regexp `json:"majorPattern,omitempty"`
 	// MinorPattern is the regex used to detect if a commit contains a minor change
 	// If no commit match RegexMajor or RegexMinor, the change is considered as a patch
 	MinorPattern *regexp.Regexp `json:"minorPattern,omitempty"`
+	// PatchPattern is the regex used to detect if a commit contains a patch change
+	// If nil, any commit that does not match RegexMajor or RegexMinor is a patch change.
+	// Otherwise, the commits that match none of the patterns do not trigger any bump.
+	PatchPattern *regexp.Regexp `json:"patchPattern,omitempty"`
+	// CommitPattern is the regex a commit header must match to follow the conventions
+	// It is only used to lint the commit messages
+	CommitPattern *regexp.Regexp `json:"commitPattern,omitempty"`
+	// Scopes are the rules restricting the bump triggered by the conventional commit scopes
+	Scopes ScopeRules `json:"scopes,omitempty"`
+	// SplitCommitBodies enables to split the body of squash merge commits in one change per conventional line item
+	// such as "* feat: add option", so each of them counts for the bump detection.
+	SplitCommitBodies bool `json:"splitCommitBodies,omitempty"`
+	// IssuePatterns are the patterns of the issue references listed in the release notes
+	IssuePatterns []IssuePattern `json:"issuePatterns,omitempty"`
+	// RepositoryURL is the web URL of the repository used to link the issues.
+	// If empty, it is computed from the URL of the remote of the fetch options, origin by default.
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// ReleaseCommitPattern is the regex matching the release commits, the commits updating the version of the manifest files.
+	// They are ignored to compute the version and the release notes. It is not set by default, see NewReleaseCommitPattern.
+	ReleaseCommitPattern *regexp.Regexp `json:"releaseCommitPattern,omitempty"`
 	// BumpStrategies is a list of bump strategies for matching branches
 	BumpStrategies []BumpBranchesStrategy `json:"bumpStrategies,omitempty"`
+	// Fetch configures how the tags are fetched from the remote repository
+	Fetch FetchOptions `json:"fetch,omitempty"`
+	// CI is the CI provider running the build, given to the templates and used to detect the pull requests.
+	// It is not detected by the strategy, use ci.Detect to set it.
+	CI ci.Provider `json:"-"`
 	// gitRepo is an implementation of GitRepo
 	gitRepo GitRepo
+	// remoteWebURL caches the web URL of the remote repository, see repositoryURL
+	remoteWebURL *string
 }
 
 /*
@@ -57,8 +93,11 @@ NewConventionalCommitBumpStrategy create a BumpStrategy following https://www.co
 
 The strategy configuration is:
 
+	Convention: conventional
 	MajorPattern: (?:^.+\!:.+|(?m)^BREAKING CHANGE:.+$)
 	MinorPattern: ^(?:feat|chore|build|ci|refactor|perf)(?:\(.+\))?:.+
+	CommitPattern: ^(?:build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(?:\(.+\))?!?: .+
+	IssuePatterns: [{Pattern: (?:\B#|\bGH-)(?P<id>\d+)\b}]
 	BumpBranchesStrategies: [
 		{
 			Strategy: AUTO
@@ -84,9 +123,12 @@ func NewConventionalCommitBumpStrategy(gitRepo GitRepo) *BumpStrategy {
 			*NewDefaultBumpBranchesStrategy(DefaultReleaseBranchesPattern),
 			*NewBuildBumpBranchesStrategy(".*", DefaultBuildMetadataTemplate),
 		},
-		MajorPattern: regexp.MustCompile(DefaultMajorPattern),
-		MinorPattern: regexp.MustCompile(DefaultMinorPattern),
-		gitRepo:      gitRepo,
+		Convention:    DefaultConvention,
+		MajorPattern:  regexp.MustCompile(DefaultMajorPattern),
+		MinorPattern:  regexp.MustCompile(DefaultMinorPattern),
+		CommitPattern: regexp.MustCompile(DefaultCommitPattern),
+		IssuePatterns: []IssuePattern{{Pattern: regexp.MustCompile(DefaultIssuePattern)}},
+		gitRepo:       gitRepo,
 	}
 }
 
@@ -94,8 +136,21 @@ func NewConventionalCommitBumpStrategy(gitRepo GitRepo) *BumpStrategy {
 func (o BumpStrategy) GoString() string {
 	var sb strings.Builder
 	sb.WriteString("version.BumpStrategy{")
-	sb.WriteString(fmt.Sprintf("MajorPattern: &regexp.Regexp{expr: %q}, MinorPattern: &regexp.Regexp{expr: %q}, ", o.MajorPattern, o.MinorPattern))
-	sb.WriteString(fmt.Sprintf("BumpBranchesStrategies: %#v", o.BumpStrategies))
+	sb.WriteString(fmt.Sprintf("Convention: %q, ", o.Convention))
+	sb.WriteString(fmt.Sprintf("MajorPattern: &regexp.Regexp{expr: %q}, MinorPattern: &regexp.Regexp{expr: %q}, PatchPattern: &regexp.Regexp{expr: %q}, CommitPattern: &regexp.Regexp{expr: %q}, ",
+		utils.RegexpToString(o.MajorPattern), utils.RegexpToString(o.MinorPattern), utils.RegexpToString(o.PatchPattern), utils.RegexpToString(o.CommitPattern)))
+	sb.WriteString(fmt.Sprintf("Scopes: %+v, SplitCommitBodies: %v, ", o.Scopes, o.SplitCommitBodies))
+	sb.WriteString("IssuePatterns: []version.IssuePattern{")
+	for i, p := range o.IssuePatterns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf("{Pattern: &regexp.Regexp{expr: %q}, URLTemplate: %q}", utils.RegexpToString(p.Pattern), utils.TemplateToString(p.URLTemplate)))
+	}
+	sb.WriteString(fmt.Sprintf("}, RepositoryURL: %q, ", o.RepositoryURL))
+	sb.WriteString(fmt.Sprintf("ReleaseCommitPattern: &regexp.Regexp{expr: %q}, ", utils.RegexpToString(o.ReleaseCommitPattern)))
+	sb.WriteString(fmt.Sprintf("BumpBranchesStrategies: %#v, ", o.BumpStrategies))
+	sb.WriteString(fmt.Sprintf("Fetch: %#v", o.Fetch))
 	sb.WriteString("}")
 	return sb.String()
 }
@@ -103,16 +158,23 @@ func (o BumpStrategy) GoString() string {
 // SetGitRepository configures the git repository to use for the strategy
 func (o *BumpStrategy) SetGitRepository(gitRepo GitRepo) {
 	o.gitRepo = gitRepo
+	o.remoteWebURL = nil
 }
 
 // Bump performs the version bumping based on the strategy
 func (o *BumpStrategy) Bump() (Version, error) {
+	version, _, err := o.BumpWithContext()
+	return version, err
+}
+
+// BumpWithContext computes the next version like Bump and returns it with the Context used to compute it.
+// The Context explains the version, eg. its ReleaseAs is set when the version is forced by a Release-As footer.
+func (o *BumpStrategy) BumpWithContext() (Version, *Context, error) {
 	log.Debug("BumpStrategy: bump with configuration: %#v", o)
 
 	// Make sure we have the tags
-	err := o.gitRepo.FetchTags()
-	if err != nil {
-		return zeroVersion, newErrorC(err, "Cannot fetch tags")
+	if err := o.fetchTags(); err != nil {
+		return zeroVersion, nil, err
 	}
 
 	// This assumes we used annotated tags for the release. Annotated tag are created with: git tag -a -m "<message>" <tag>
@@ -120,87 +182,164 @@ func (o *BumpStrategy) Bump() (Version, error) {
 	// Thanks to that git describe will only show the more recent annotated tag if many annotated tags are on the same commit.
 	// However if you use lightweight tags there are many on the same commit, it just takes the first one.
 	lastTag, err := o.gitRepo.GetLastRelativeTag("HEAD")
+	if errors.Is(err, ErrShallowClone) {
+		return zeroVersion, nil, newErrorC(err, "Cannot get last relative tag")
+	}
 	if err != nil {
 		// just log for debug but the program can continue
 		log.Debug("%v", newErrorC(err, "Unable to get last relative tag"))
 	}
 
-	// Parse the last version from the tag name
-	lastVersion, err := NewVersion(extractVersionFromTag(lastTag.Name))
+	currentBranch, err := o.gitRepo.GetCurrentBranch()
 	if err != nil {
-		return zeroVersion, err
+		return zeroVersion, nil, newErrorC(err, "Cannot get current branch name")
 	}
 
-	currentBranch, err := o.gitRepo.GetCurrentBranch()
+	return o.computeVersion(lastTag, "HEAD", currentBranch)
+}
+
+// computeVersion computes the version at a revision from the last tag and returns it with the Context used to compute it
+func (o *BumpStrategy) computeVersion(lastTag git.Tag, rev string, branch string) (Version, *Context, error) {
+	// Parse the last version from the tag name
+	lastVersion, err := NewVersion(extractVersionFromTag(lastTag.Name))
 	if err != nil {
-		return zeroVersion, newErrorC(err, "Cannot get current branch name")
+		return zeroVersion, nil, err
 	}
 
 	// Check if describe is a tag, if so return the version that matches this tag
-	commits, cErr := o.gitRepo.GetCommits(lastTag.Name, "HEAD")
-	if cErr != nil {
-		// Oops
-		return zeroVersion, err
+	commits, err := o.gitRepo.GetCommits(lastTag.Name, rev)
+	if err != nil {
+		// Oops, there is probably no commit yet
+		log.Debug("%v", newErrorC(err, "Unable to get commits"))
+		return zeroVersion, o.newContext(branch, &lastVersion, &lastTag, nil), nil
 	}
 
-	context := NewContext(currentBranch, &lastVersion, &lastTag, commits)
+	context := o.newContext(branch, &lastVersion, &lastTag, o.excludeReleaseCommits(commits))
+	context.Issues = o.changesIssues(context.Changes)
 
-	log.Debug("BumpStrategy: look for appropriate version bumper with %#v, lastVersion=%v, branch=%v", lastTag, lastVersion, currentBranch)
-	versionBumper := o.computeVersionBumper(context)
+	log.Debug("BumpStrategy: look for appropriate version bumper with %#v, lastVersion=%v, branch=%v", lastTag, lastVersion, branch)
+	// only the version of HEAD depends on the working tree
+	versionBumper, err := o.computeVersionBumper(context, rev == "HEAD")
+	if err != nil {
+		return zeroVersion, nil, err
+	}
 
 	// Bump the version
-	return versionBumper(lastVersion), nil
+	return versionBumper(lastVersion), context, nil
 }
 
 func extractVersionFromTag(tagName string) string {
 	return tagName[strings.LastIndex(tagName, "/")+1:]
 }
 
-// computeAutoVersionBumper computes what bump strategy to apply
-func (o *BumpStrategy) computeVersionBumper(context *Context) versionBumper {
+// computeVersionBumper computes what bump strategy to apply.
+// If dirty is true, the working tree is checked unless the dirty policy of the matching strategy ignores it.
+func (o *BumpStrategy) computeVersionBumper(context *Context, dirty bool) (versionBumper, error) {
 	for _, it := range o.BumpStrategies {
-		if it.BranchesPattern.MatchString(context.Branch) {
+		if it.matches(context) {
 			if log.IsLevelEnabled(log.DebugLevel) {
 				log.Debug("BumpStrategy: will use bump %s", strings.ToUpper(it.Strategy.String()))
 			}
 
+			// the working tree is not queried if its changes are ignored, eg. it does not exist in a bare repository
+			var err error
+			if dirty && it.DirtyPolicy != DirtyIgnore {
+				if context.Dirty, err = o.gitRepo.IsDirty(); err != nil {
+					return nil, newErrorC(err, "Cannot get the status of the working tree")
+				}
+			}
+
 			// find the correct bumper
+			var bumper versionBumper
 			if val, ok := strategyVersionBumperMap[it.Strategy]; ok {
-				return it.createVersionBumperFrom(val, context)
+				bumper = it.createVersionBumperFrom(val, context)
 			} else if it.Strategy == AUTO {
-				return o.computeSemverBumperFromCommits(&it, context)
+				bumper, err = o.computeSemverBumperFromCommits(&it, context)
+			} else if it.Strategy == EXEC {
+				bumper, err = it.computeExecVersionBumper(context)
+			} else {
+				continue
+			}
+			if err != nil {
+				return nil, err
 			}
+			return it.DirtyPolicy.applyDirtyPolicy(bumper, context)
 		}
 	}
 
 	log.Debug("BumpStrategy: not matching strategy found in %#v. versionBumperIdentity will be used", o.BumpStrategies)
-	return versionBumperIdentity
+	return versionBumperIdentity, nil
 }
 
-func (o *BumpStrategy) computeSemverBumperFromCommits(bbs *BumpBranchesStrategy, context *Context) versionBumper {
+func (o *BumpStrategy) computeSemverBumperFromCommits(bbs *BumpBranchesStrategy, context *Context) (versionBumper, error) {
 	if len(context.Commits) == 0 {
 		log.Debug("BumpStrategy: will not use identity bump strategy because there is not commit")
-		return versionBumperIdentity
+		return versionBumperIdentity, nil
 	}
 
-	strategy := PATCH
-	bumper := Version.BumpPatch
-	for _, commit := range context.Commits {
-		if o.MajorPattern.MatchString(commit.Message) {
+	releaseAs, err := o.computeReleaseAs(context)
+	if err != nil {
+		return nil, err
+	}
+	if releaseAs != nil {
+		log.Info("Version %v is forced by a %s footer instead of the computed bump", releaseAs.Version, ReleaseAsToken)
+		context.ReleaseAs = releaseAs
+		return bbs.createVersionBumperFrom(func(Version) Version { return releaseAs.Version }, context), nil
+	}
+
+	strategy, ok := o.computeBumpStrategyType(context)
+	if !ok {
+		log.Debug("BumpStrategy: will use identity bump strategy because no commit requires a bump")
+		return bbs.createVersionBumperFrom(versionBumperIdentity, context), nil
+	}
+	log.Debug("BumpStrategy: will use bump %s strategy", strategy)
+	return bbs.createVersionBumperFrom(strategyVersionBumperMap[strategy], context), nil
+}
+
+// computeBumpStrategyType computes which part of the version the commits of the context require to bump.
+// The revert commits and the commits they revert cancel each other.
+// It returns false if no commit requires a bump because of the patch pattern, the scope rules or the reverts.
+func (o *BumpStrategy) computeBumpStrategyType(context *Context) (BumpStrategyType, bool) {
+	strategy, found := PATCH, false
+	for _, commit := range cancelReverts(context.Changes) {
+		level, ok := o.computeCommitBumpStrategyType(commit)
+		if !ok {
+			log.Trace("BumpStrategy: ignores %#v because of the patch pattern or its scope", commit)
+			continue
+		}
+		found = true
+		if level == MAJOR {
 			if context.LastVersion.IsUnstable() {
 				log.Trace("BumpStrategy: detects a MAJOR change at %#v however the last version is unstable so it will use bump MINOR strategy", commit)
-				return bbs.createVersionBumperFrom(Version.BumpMinor, context)
+				return MINOR, true
 			}
 			log.Debug("BumpStrategy: detects a MAJOR change at %#v", commit)
-			return bbs.createVersionBumperFrom(Version.BumpMajor, context)
+			return MAJOR, true
 		}
-		if o.MinorPattern.MatchString(commit.Message) {
+		if level == MINOR {
 			strategy = MINOR
 			log.Trace("BumpStrategy: detects a MINOR change at %#v", commit)
-			bumper = Version.BumpMinor
 		}
 	}
+	return strategy, found
+}
+
+// computeCommitBumpStrategyType computes which part of the version a commit requires to bump.
+// It returns false if the commit does not require any bump because of the patch pattern or the scope rules.
+func (o *BumpStrategy) computeCommitBumpStrategyType(commit git.Commit) (BumpStrategyType, bool) {
+	level := PATCH
+	if matchPattern(o.MajorPattern, commit.Message) || o.isBreakingChange(commit) {
+		level = MAJOR
+	} else if matchPattern(o.MinorPattern, commit.Message) {
+		level = MINOR
+	} else if o.PatchPattern != nil && !o.PatchPattern.MatchString(commit.Message) {
+		return PATCH, false
+	}
+	return o.Scopes.apply(o.parseMessage(commit.Message).Scope, level)
+}
 
-	log.Debug("BumpStrategy: will use bump %s strategy", strategy)
-	return bbs.createVersionBumperFrom(bumper, context)
+// isBreakingChange returns true if the commit is valid for the convention and declares a breaking change
+func (o *BumpStrategy) isBreakingChange(commit git.Commit) bool {
+	c := o.parseMessage(commit.Message)
+	return c.Valid && c.Breaking
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
 	"github.com/arnaud-deprez/gsemver/pkg/git"
 	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
 )
@@ -408,6 +409,51 @@ func TestBumpVersionStrategyAutoBreakingChangeShortForm(t *testing.T) {
 	}
 }
 
+func TestBumpVersionStrategyAutoConventionalCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		message               string
+		buildMetadataTemplate string
+		expected              string
+	}{
+		{"feat: add option\n\nBREAKING-CHANGE: option replaces flag", "", "2.0.0"},
+		{"fix(version)!: drop flag", "", "2.0.0"},
+		// not a conventional commit so it is not a breaking change
+		{"update version\n\nBREAKING-CHANGE: option replaces flag", "", "1.1.1"},
+		{"feat(api): add option", "{{range .Commits}}{{.Conventional.Type}}.{{.Conventional.Scope}}{{end}}", "1.1.0+feat.api"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+			gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{
+				{
+					Author:    git.Signature{Name: "Arnaud Deprez", Email: "xxx@example.com"},
+					Committer: git.Signature{Name: "Arnaud Deprez", Email: "xxx@example.com"},
+					Hash:      git.Hash("1234567890"),
+					Message:   tc.message,
+				},
+			}, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.BumpStrategies = []BumpBranchesStrategy{*NewBumpAllBranchesStrategy(AUTO, false, "", false, "")}
+			strategy.BumpStrategies[0].BuildMetadataTemplate = NewBuildBumpBranchesStrategy(".*", tc.buildMetadataTemplate).BuildMetadataTemplate
+			version, err := strategy.Bump()
+
+			assert.Nil(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
+
 func TestBumpVersionStrategyAutoWithNewFeature(t *testing.T) {
 	assert := assert.New(t)
 
@@ -500,6 +546,7 @@ Closes #123`,
 				},
 			}, nil)
 			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return(tc.branch, nil)
+			gitRepo.EXPECT().GetRemoteURL().Times(1).Return("git@github.com:owner/repo.git", nil)
 
 			strategy := NewConventionalCommitBumpStrategy(gitRepo)
 			version, err := strategy.Bump()
@@ -642,6 +689,22 @@ func TestBumpVersionStrategyAutoWithPreReleaseMavenLike(t *testing.T) {
 	assert.Equal("1.1.0-SNAPSHOT", version.String())
 }
 
+func TestBumpVersionStrategyShallowClone(t *testing.T) {
+	assert := assert.New(t)
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{}, fmt.Errorf("%w: 400 commits fetched", ErrShallowClone))
+
+	// the version must not restart from 0.0.0
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	_, err := strategy.Bump()
+	assert.EqualError(err, "Cannot get last relative tag caused by: no tag found in the shallow clone, fetch the full history with git fetch --unshallow: 400 commits fetched")
+}
+
 func TestSetGitRepository(t *testing.T) {
 	assert := assert.New(t)
 	s := &BumpStrategy{}
@@ -658,5 +721,33 @@ func ExampleBumpStrategy_GoString() {
 	gitRepo := mock_version.NewMockGitRepo(nil)
 	s := NewConventionalCommitBumpStrategy(gitRepo)
 	fmt.Printf("%#v\n", s)
-	// Output: version.BumpStrategy{MajorPattern: &regexp.Regexp{expr: "(?:^.+\\!:.+|(?m)^BREAKING CHANGE:.+$)"}, MinorPattern: &regexp.Regexp{expr: "^(?:feat|chore|build|ci|refactor|perf)(?:\\(.+\\))?:.+"}, BumpBranchesStrategies: []version.BumpBranchesStrategy{version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: "^(main|master|release/.*)$"}, PreRelease: false, PreReleaseTemplate: &template.Template{text: ""}, PreReleaseOverwrite: false, BuildMetadataTemplate: &template.Template{text: ""}}, version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: ".*"}, PreRelease: false, PreReleaseTemplate: &template.Template{text: ""}, PreReleaseOverwrite: false, BuildMetadataTemplate: &template.Template{text: "{{.Commits | len}}.{{(.Commits | first).Hash.Short}}"}}}}
+	// Output: version.BumpStrategy{Convention: "conventional", MajorPattern: &regexp.Regexp{expr: "(?:^.+\\!:.+|(?m)^BREAKING CHANGE:.+$)"}, MinorPattern: &regexp.Regexp{expr: "^(?:feat|chore|build|ci|refactor|perf)(?:\\(.+\\))?:.+"}, PatchPattern: &regexp.Regexp{expr: ""}, CommitPattern: &regexp.Regexp{expr: "^(?:build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(?:\\(.+\\))?!?: .+"}, Scopes: {Ignore:[] Major:{Allow:[] Deny:[]} Minor:{Allow:[] Deny:[]} Patch:{Allow:[] Deny:[]}}, SplitCommitBodies: false, IssuePatterns: []version.IssuePattern{{Pattern: &regexp.Regexp{expr: "(?:\\B#|\\bGH-)(?P<id>\\d+)\\b"}, URLTemplate: ""}}, RepositoryURL: "", ReleaseCommitPattern: &regexp.Regexp{expr: ""}, BumpBranchesStrategies: []version.BumpBranchesStrategy{version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: "^(main|master|release/.*)$"}, PullRequest: nil, TargetBranchesPattern: &regexp.Regexp{expr: ""}, PreRelease: false, PreReleaseTemplate: &template.Template{text: ""}, PreReleaseOverwrite: false, BuildMetadataTemplate: &template.Template{text: ""}, Command: "", CommandTimeout: 0s, DirtyPolicy: ignore}, version.BumpBranchesStrategy{Strategy: AUTO, BranchesPattern: &regexp.Regexp{expr: ".*"}, PullRequest: nil, TargetBranchesPattern: &regexp.Regexp{expr: ""}, PreRelease: false, PreReleaseTemplate: &template.Template{text: ""}, PreReleaseOverwrite: false, BuildMetadataTemplate: &template.Template{text: "{{.Commits | len}}.{{(.Commits | first).Hash.Short}}"}, Command: "", CommandTimeout: 0s, DirtyPolicy: ignore}}, Fetch: version.FetchOptions{Skip: false, Remote: "", Refspec: "", IgnoreErrors: false}}
+}
+
+func TestBumpVersionStrategyPullRequest(t *testing.T) {
+	assert := assert.New(t)
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("feature/one", nil)
+	commits := []git.Commit{}
+	for i := 0; i < 5; i++ {
+		commits = append(commits, git.Commit{Hash: git.Hash(fmt.Sprintf("%d234567890", i)), Message: "feat: feature one"})
+	}
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return(commits, nil)
+
+	isPullRequest := true
+	pullRequest := *NewPreReleaseBumpBranchesStrategy(".*", "pr.{{.CI.PullRequest.Number}}.{{.Commits | len}}", true)
+	pullRequest.PullRequest = &isPullRequest
+	pullRequest.TargetBranchesPattern = regexp.MustCompile("^main$")
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.BumpStrategies = []BumpBranchesStrategy{pullRequest, *NewBuildBumpBranchesStrategy(".*", DefaultBuildMetadataTemplate)}
+	strategy.CI = ci.Provider{Name: ci.GitHubActions, Branch: "feature/one", PullRequest: &ci.PullRequest{Number: "42", SourceBranch: "feature/one", TargetBranch: "main"}}
+	version, err := strategy.Bump()
+	assert.NoError(err)
+	assert.Equal("1.3.0-pr.42.5", version.String())
 }
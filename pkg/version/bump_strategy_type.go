@@ -17,9 +17,11 @@ const (
 	MAJOR
 	// AUTO means to apply the automatic strategy based on commit history
 	AUTO
+	// EXEC means to delegate the bump decision to an external command
+	EXEC
 )
 
-var bumpStrategyToString = []string{"PATCH", "MINOR", "MAJOR", "AUTO"}
+var bumpStrategyToString = []string{"PATCH", "MINOR", "MAJOR", "AUTO", "EXEC"}
 
 // ParseBumpStrategyType converts string value to BumpStrategy
 func ParseBumpStrategyType(value string) BumpStrategyType {
@@ -30,6 +32,8 @@ func ParseBumpStrategyType(value string) BumpStrategyType {
 		return MINOR
 	case "patch":
 		return PATCH
+	case "exec":
+		return EXEC
 	default:
 		return AUTO
 	}
@@ -18,6 +18,7 @@ func TestMarshallBumpStrategyType(t *testing.T) {
 		{MINOR, `"MINOR"`},
 		{MAJOR, `"MAJOR"`},
 		{AUTO, `"AUTO"`},
+		{EXEC, `"EXEC"`},
 	}
 
 	for _, tc := range testData {
@@ -42,6 +43,8 @@ func TestUnMarshallBumpStrategyType(t *testing.T) {
 		{`"minor"`, MINOR},
 		{`"major"`, MAJOR},
 		{`"auto"`, AUTO},
+		{`"EXEC"`, EXEC},
+		{`"exec"`, EXEC},
 		{`"foo"`, AUTO}, // fallback to AUTO if unknown value
 	}
 
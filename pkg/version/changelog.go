@@ -0,0 +1,332 @@
+package version
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// DefaultChangelogTemplate is the default template of the release notes of a version in Markdown.
+	// Its data is a Release.
+	DefaultChangelogTemplate = `## {{.Version}}{{if not .Date.IsZero}} ({{.Date.Format "2006-01-02"}}){{end}}
+{{- if .BreakingChanges}}
+
+### ⚠ BREAKING CHANGES
+{{range .BreakingChanges}}
+* {{if .Scope}}**{{.Scope}}:** {{end}}{{.BreakingChange}}
+{{- end}}
+{{- end}}
+{{- range .Sections}}
+
+### {{.Title}}
+{{range .Entries}}
+* {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.Commit.Hash.Short}})
+{{- end}}
+{{- end}}
+`
+
+	// otherChangesTitle is the title of the section of the commits that do not follow the convention
+	otherChangesTitle = "Other Changes"
+)
+
+var (
+	// changelogSectionTitles are the titles of the sections of the well-known commit types, in the order of the release notes
+	changelogSectionTitles = []struct{ Type, Title string }{
+		{"feat", "Features"},
+		{"fix", "Bug Fixes"},
+		{"perf", "Performance Improvements"},
+		{"revert", "Reverts"},
+		{"docs", "Documentation"},
+		{"refactor", "Code Refactoring"},
+		{"style", "Styles"},
+		{"test", "Tests"},
+		{"build", "Build System"},
+		{"ci", "Continuous Integration"},
+		{"chore", "Chores"},
+	}
+	// mergeCommitRegex matches the merge commits generated by git or the git hosting services
+	mergeCommitRegex = regexp.MustCompile(`^Merge `)
+)
+
+// Release represents the release notes of a version
+type Release struct {
+	// Version is the version of the release
+	Version Version `json:"version"`
+	// Tag is the tag of the release, nil if the version is not released yet
+	Tag *git.Tag `json:"tag,omitempty"`
+	// PreviousTag is the tag the changes are computed from, nil for the first release
+	PreviousTag *git.Tag `json:"previousTag,omitempty"`
+	// Date is the date of the tag or of the most recent commit of the release
+	Date time.Time `json:"date"`
+	// RepositoryURL is the web URL of the repository, empty if it is unknown
+	RepositoryURL string `json:"repositoryUrl,omitempty"`
+	// BreakingChanges are the changes triggering a major bump
+	BreakingChanges []ChangelogEntry `json:"breakingChanges,omitempty"`
+	// Sections are the changes grouped by commit type
+	Sections []ChangelogSection `json:"sections"`
+	// Issues are the issues referenced by the changes, in order of appearance
+	Issues []IssueReference `json:"issues,omitempty"`
+}
+
+// ChangelogSection represents the changes of a commit type
+type ChangelogSection struct {
+	// Type is the commit type, empty for the commits that do not follow the convention
+	Type string `json:"type"`
+	// Title is the title of the section such as Features for feat
+	Title string `json:"title"`
+	// Entries are the changes of the section from the oldest to the most recent
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+// ChangelogEntry represents a change parsed with the convention
+type ChangelogEntry struct {
+	git.ConventionalCommit
+	// Commit is the commit of the change
+	Commit git.Commit `json:"commit"`
+	// Issues are the issues referenced by the change
+	Issues []IssueReference `json:"issues,omitempty"`
+}
+
+// Release computes the release notes of the commits after from (excluded) up to to.
+//
+// If to is empty, it is HEAD. If to has a version tag, the release has its version, otherwise the release has the
+// version computed like Bump would. If from is empty, the release starts at the previous tag.
+func (o *BumpStrategy) Release(from string, to string) (*Release, error) {
+	log.Debug("BumpStrategy: release notes with configuration: %#v", o)
+
+	// Make sure we have the tags
+	if err := o.fetchTags(); err != nil {
+		return nil, err
+	}
+	return o.release(from, to, o.repositoryURL())
+}
+
+// release computes the release notes of the commits after from (excluded) up to to, see Release
+func (o *BumpStrategy) release(from string, to string, repositoryURL string) (*Release, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	tags, err := o.gitRepo.GetTagsPointingAt(to)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get tags of %s", to)
+	}
+	if tag := lastVersionTag(tags); tag != nil {
+		return o.tagRelease(*tag, from, repositoryURL)
+	}
+
+	lastTag, err := o.gitRepo.GetLastRelativeTag(to)
+	if errors.Is(err, ErrShallowClone) {
+		return nil, newErrorC(err, "Cannot get last relative tag of %s", to)
+	}
+	if err != nil {
+		log.Debug("%v", newErrorC(err, "Unable to get last relative tag of %s", to))
+	}
+	currentBranch, err := o.gitRepo.GetCurrentBranch()
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get current branch name")
+	}
+	v, context, err := o.computeVersion(lastTag, to, currentBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := context.Commits
+	var previousTag *git.Tag
+	if from != "" {
+		if commits, err = o.gitRepo.GetCommits(from, to); err != nil {
+			return nil, newErrorC(err, "Cannot get commits between %s and %s", from, to)
+		}
+	} else if lastTag.Name != "" {
+		previousTag = &lastTag
+	}
+	return o.newRelease(v, nil, previousTag, commits, repositoryURL), nil
+}
+
+// Releases computes the release notes of every version tag and of the unreleased commits if there are any.
+// The releases are ordered from the most recent to the oldest.
+func (o *BumpStrategy) Releases() ([]Release, error) {
+	log.Debug("BumpStrategy: release notes of every version with configuration: %#v", o)
+
+	// Make sure we have the tags
+	if err := o.fetchTags(); err != nil {
+		return nil, err
+	}
+	repositoryURL := o.repositoryURL()
+
+	unreleased, err := o.release("", "HEAD", repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := o.gitRepo.GetTags()
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get tags")
+	}
+	sortTags(tags)
+
+	var releases []Release
+	if unreleased.Tag == nil && len(unreleased.Sections) > 0 {
+		releases = append(releases, *unreleased)
+	}
+	for i := len(tags) - 1; i >= 0; i-- {
+		if _, err := NewVersion(extractVersionFromTag(tags[i].Name)); err != nil {
+			continue
+		}
+		r, err := o.tagRelease(tags[i], "", repositoryURL)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, *r)
+	}
+	return releases, nil
+}
+
+// tagRelease computes the release notes of a version tag from a revision, by default its previous tag
+func (o *BumpStrategy) tagRelease(tag git.Tag, from string, repositoryURL string) (*Release, error) {
+	v, err := NewVersion(extractVersionFromTag(tag.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	var previousTag *git.Tag
+	if from == "" {
+		t, err := o.gitRepo.GetLastRelativeTag(tag.Name + "^")
+		if errors.Is(err, ErrShallowClone) {
+			return nil, newErrorC(err, "Cannot get previous tag of %s", tag.Name)
+		}
+		if err != nil {
+			// this is the first release
+			log.Debug("%v", newErrorC(err, "Unable to get previous tag of %s", tag.Name))
+		} else {
+			previousTag, from = &t, t.Name
+		}
+	}
+
+	commits, err := o.gitRepo.GetCommits(from, tag.Name)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get commits of %s", tag.Name)
+	}
+	return o.newRelease(v, &tag, previousTag, commits, repositoryURL), nil
+}
+
+// newRelease groups the changes of the commits by type and collects the issues they reference.
+// The commits are expected from the most recent to the oldest, like git log.
+func (o *BumpStrategy) newRelease(v Version, tag *git.Tag, previousTag *git.Tag, commits []git.Commit, repositoryURL string) *Release {
+	ret := &Release{Version: v, Tag: tag, PreviousTag: previousTag, RepositoryURL: repositoryURL}
+	switch {
+	case tag != nil && !tag.Tagger.When.IsZero():
+		ret.Date = tag.Tagger.When
+	case len(commits) > 0:
+		ret.Date = commits[0].Committer.When
+	}
+
+	changes := commits
+	if o.SplitCommitBodies {
+		changes = o.splitCommits(commits)
+	}
+	changes = cancelReverts(changes)
+
+	sections := map[string]*ChangelogSection{}
+	var types []string
+	issues := map[string]bool{}
+	for i := len(changes) - 1; i >= 0; i-- {
+		commit := changes[i]
+		if mergeCommitRegex.MatchString(commit.Message) || o.isReleaseCommit(commit) {
+			continue
+		}
+		entry := ChangelogEntry{ConventionalCommit: o.parseMessage(commit.Message), Commit: commit}
+		entry.Issues = o.issueReferences(commit.Message, repositoryURL)
+		for _, issue := range entry.Issues {
+			if !issues[issue.key()] {
+				issues[issue.key()] = true
+				ret.Issues = append(ret.Issues, issue)
+			}
+		}
+		if o.isBreakingChange(commit) || matchPattern(o.MajorPattern, commit.Message) {
+			if entry.BreakingChange == "" {
+				entry.BreakingChange = entry.Description
+			}
+			ret.BreakingChanges = append(ret.BreakingChanges, entry)
+		}
+
+		t := ""
+		if entry.Valid {
+			t = entry.Type
+		}
+		if sections[t] == nil {
+			sections[t] = &ChangelogSection{Type: t, Title: changelogSectionTitle(t)}
+			types = append(types, t)
+		}
+		sections[t].Entries = append(sections[t].Entries, entry)
+	}
+
+	sort.SliceStable(types, func(i, j int) bool {
+		return changelogSectionOrder(types[i]) < changelogSectionOrder(types[j])
+	})
+	ret.Sections = make([]ChangelogSection, len(types))
+	for i, t := range types {
+		ret.Sections[i] = *sections[t]
+	}
+	return ret
+}
+
+// changelogSectionTitle returns the title of the section of a commit type
+func changelogSectionTitle(commitType string) string {
+	if commitType == "" {
+		return otherChangesTitle
+	}
+	for _, it := range changelogSectionTitles {
+		if it.Type == commitType {
+			return it.Title
+		}
+	}
+	return commitType
+}
+
+// changelogSectionOrder returns the rank of the section of a commit type:
+// the well-known types first, then the other types and finally the commits that do not follow the convention.
+func changelogSectionOrder(commitType string) int {
+	if commitType == "" {
+		return len(changelogSectionTitles) + 1
+	}
+	for i, it := range changelogSectionTitles {
+		if it.Type == commitType {
+			return i
+		}
+	}
+	return len(changelogSectionTitles)
+}
+
+// lastVersionTag returns the tag with the highest version or nil if none of the tags is a version
+func lastVersionTag(tags []git.Tag) *git.Tag {
+	sortTags(tags)
+	for i := len(tags) - 1; i >= 0; i-- {
+		if _, err := NewVersion(extractVersionFromTag(tags[i].Name)); err == nil {
+			return &tags[i]
+		}
+	}
+	return nil
+}
+
+// RenderChangelog renders the release notes of each release with the template, separated by a blank line
+func RenderChangelog(w io.Writer, t *template.Template, releases []Release) error {
+	for i, r := range releases {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := t.Execute(w, r); err != nil {
+			return newErrorC(err, "Cannot render the release notes of %v", r.Version)
+		}
+	}
+	return nil
+}
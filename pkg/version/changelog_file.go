@@ -0,0 +1,161 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultChangelogHeader is the header of a new changelog file with the default layout
+	DefaultChangelogHeader = "# Changelog\n"
+	// KeepAChangelogHeader is the header of a new changelog file with the https://keepachangelog.com layout
+	KeepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+	// KeepAChangelogTemplate is the template of the release notes of a version with the https://keepachangelog.com layout.
+	// Features are Added, breaking changes, performance improvements and refactorings are Changed,
+	// reverts are Removed and bug fixes are Fixed. The other changes are left out.
+	KeepAChangelogTemplate = `## [{{.Version}}]{{if not .Date.IsZero}} - {{.Date.Format "2006-01-02"}}{{end}}
+{{- with .Entries "feat"}}
+
+### Added
+{{range .}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{- end}}
+{{- end}}
+{{- if or .BreakingChanges (.Entries "perf" "refactor")}}
+
+### Changed
+{{range .BreakingChanges}}
+- **BREAKING:** {{if .Scope}}**{{.Scope}}:** {{end}}{{.BreakingChange}}
+{{- end}}
+{{- range .Entries "perf" "refactor"}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{- end}}
+{{- end}}
+{{- with .Entries "revert"}}
+
+### Removed
+{{range .}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{- end}}
+{{- end}}
+{{- with .Entries "fix"}}
+
+### Fixed
+{{range .}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}
+{{- end}}
+{{- end}}
+`
+)
+
+var (
+	// changelogVersionHeadingRegex matches the heading of the release notes of a version such as "## 1.2.0" or "## [v1.2.0] - 2024-03-01"
+	/* const */ changelogVersionHeadingRegex = regexp.MustCompile(`^## \[?([^\]\s]+)\]?`)
+)
+
+// changelogBlock is a part of a changelog file starting with a "## " heading
+type changelogBlock struct {
+	text    string
+	version *Version
+}
+
+// Entries returns the entries of the sections of the commit types, in the order of the types
+func (r Release) Entries(types ...string) []ChangelogEntry {
+	var ret []ChangelogEntry
+	for _, t := range types {
+		for _, s := range r.Sections {
+			if s.Type == t {
+				ret = append(ret, s.Entries...)
+			}
+		}
+	}
+	return ret
+}
+
+/*
+UpdateChangelog inserts the release notes of the releases rendered with the template in the content of a changelog file.
+
+The release notes of a version are delimited by a "## " heading followed by the version, eg. "## 1.2.0" or "## [1.2.0]".
+The release notes of a version already in the content are replaced, so updating the content twice with the same
+releases gives the same result. The others are inserted above the release notes of the previous version and below any
+heading that is not a version such as "## [Unreleased]". The rest of the content is preserved.
+If the content is empty, it starts with the header.
+*/
+func UpdateChangelog(content string, header string, t *template.Template, releases []Release) (string, error) {
+	if strings.TrimSpace(content) == "" {
+		content = header
+	}
+	preamble, blocks := splitChangelog(content)
+
+	for _, r := range releases {
+		var sb strings.Builder
+		if err := RenderChangelog(&sb, t, []Release{r}); err != nil {
+			return "", err
+		}
+		v := r.Version
+		block := changelogBlock{text: strings.TrimRight(sb.String(), "\n") + "\n\n", version: &v}
+
+		index := len(blocks)
+		for i, b := range blocks {
+			if b.version == nil {
+				continue
+			}
+			if b.version.String() == v.String() {
+				blocks[i] = block
+				index = -1
+				break
+			}
+			if b.version.Compare(v) < 0 && index == len(blocks) {
+				index = i
+			}
+		}
+		if index >= 0 {
+			blocks = append(blocks[:index], append([]changelogBlock{block}, blocks[index:]...)...)
+		}
+	}
+
+	var sb strings.Builder
+	if preamble = strings.TrimRight(preamble, "\n"); preamble != "" {
+		sb.WriteString(preamble + "\n\n")
+	}
+	for _, b := range blocks {
+		sb.WriteString(b.text)
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n", nil
+}
+
+// splitChangelog splits the content of a changelog file in the text before the first "## " heading and the blocks starting with a "## " heading
+func splitChangelog(content string) (string, []changelogBlock) {
+	var preamble strings.Builder
+	var blocks []changelogBlock
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			block := changelogBlock{}
+			if m := changelogVersionHeadingRegex.FindStringSubmatch(line); m != nil {
+				if v, err := NewVersion(m[1]); err == nil {
+					block.version = &v
+				}
+			}
+			blocks = append(blocks, block)
+		}
+		if len(blocks) == 0 {
+			preamble.WriteString(line)
+		} else {
+			blocks[len(blocks)-1].text += line
+		}
+	}
+	// the last block must end with a blank line before any block is appended
+	if n := len(blocks); n > 0 {
+		blocks[n-1].text = strings.TrimRight(blocks[n-1].text, "\n") + "\n\n"
+	}
+	return preamble.String(), blocks
+}
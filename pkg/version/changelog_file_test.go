@@ -0,0 +1,98 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+func TestUpdateChangelog(t *testing.T) {
+	release := func(v string, message string) Release {
+		version, _ := NewVersion(v)
+		commit := git.Commit{Hash: git.Hash("1234567890"), Message: message}
+		s := NewConventionalCommitBumpStrategy(nil)
+		return *s.newRelease(version, nil, nil, []git.Commit{commit}, "")
+	}
+	tmpl := utils.NewTemplate("## {{.Version}}\n{{range .Sections}}{{range .Entries}}\n* {{.Description}}{{end}}{{end}}\n")
+
+	testData := []struct {
+		name     string
+		content  string
+		releases []Release
+		expected string
+	}{
+		{
+			"new file",
+			"",
+			[]Release{release("1.0.0", "feat: first")},
+			"# Changelog\n\n## 1.0.0\n\n* first\n",
+		},
+		{
+			"insert above the previous version and preserve hand-edited sections",
+			"# Changelog\n\nIntro.\n\n## 1.0.0\n\n* first, edited by hand\n",
+			[]Release{release("1.1.0", "feat: second")},
+			"# Changelog\n\nIntro.\n\n## 1.1.0\n\n* second\n\n## 1.0.0\n\n* first, edited by hand\n",
+		},
+		{
+			"replace the same version",
+			"# Changelog\n\n## 1.1.0\n\n* second\n\n## 1.0.0\n\n* first\n",
+			[]Release{release("1.1.0", "feat: second and third")},
+			"# Changelog\n\n## 1.1.0\n\n* second and third\n\n## 1.0.0\n\n* first\n",
+		},
+		{
+			"below unreleased and in version order",
+			"# Changelog\n\n## [Unreleased]\n\n## [v1.0.0] - 2024-01-01\n\n* first\n",
+			[]Release{release("1.2.0", "feat: third"), release("0.9.0", "feat: zero")},
+			"# Changelog\n\n## [Unreleased]\n\n## 1.2.0\n\n* third\n\n## [v1.0.0] - 2024-01-01\n\n* first\n\n## 0.9.0\n\n* zero\n",
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := UpdateChangelog(tc.content, DefaultChangelogHeader, tmpl, tc.releases)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			// updating again gives the same content
+			again, err := UpdateChangelog(actual, DefaultChangelogHeader, tmpl, tc.releases)
+			assert.NoError(t, err)
+			assert.Equal(t, actual, again)
+		})
+	}
+}
+
+func TestKeepAChangelogTemplate(t *testing.T) {
+	commits := []git.Commit{
+		{Hash: git.Hash("5555555555"), Message: "chore: tidy"},
+		{Hash: git.Hash("4444444444"), Message: "fix(api): handle empty body"},
+		{Hash: git.Hash("3333333333"), Message: "perf: cache the parser"},
+		{Hash: git.Hash("2222222222"), Message: "feat!: drop v1 API"},
+		{Hash: git.Hash("1111111111"), Message: "feat: add endpoint", Committer: git.Signature{When: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	s := NewConventionalCommitBumpStrategy(nil)
+	r := s.newRelease(Version{Major: 2}, nil, nil, commits, "")
+
+	actual, err := UpdateChangelog("", KeepAChangelogHeader, utils.NewTemplate(KeepAChangelogTemplate), []Release{*r})
+	assert.NoError(t, err)
+	assert.Equal(t, KeepAChangelogHeader+`
+## [2.0.0]
+
+### Added
+
+- add endpoint
+- drop v1 API
+
+### Changed
+
+- **BREAKING:** drop v1 API
+- cache the parser
+
+### Fixed
+
+- **api:** handle empty body
+`, actual)
+}
@@ -0,0 +1,183 @@
+package version
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestReleaseUnreleased(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	when := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "feat(api): add endpoint"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "fix: handle empty body (#7)\n\nCloses: GH-7, #8"}
+	c3 := git.Commit{Hash: git.Hash("3333333333"), Message: "Merge pull request #12 from owner/branch"}
+	c4 := git.Commit{Hash: git.Hash("4444444444"), Message: "update the README"}
+	c5 := git.Commit{Hash: git.Hash("5555555555"), Message: "feat!: drop v1 API\n\nBREAKING CHANGE: v1 endpoints are removed"}
+	c6 := git.Commit{Hash: git.Hash("6666666666"), Message: "chore: tidy", Committer: git.Signature{When: when}}
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("git@github.com:owner/repo.git", nil)
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{{Name: "latest"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return([]git.Commit{c6, c5, c4, c3, c2, c1}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	r, err := strategy.Release("", "")
+	assert.NoError(err)
+	assert.Equal("2.0.0", r.Version.String())
+	assert.Nil(r.Tag)
+	assert.Equal(&git.Tag{Name: "v1.2.0"}, r.PreviousTag)
+	assert.Equal(when, r.Date)
+
+	titles := make([]string, len(r.Sections))
+	for i, s := range r.Sections {
+		titles[i] = s.Title
+	}
+	assert.Equal([]string{"Features", "Bug Fixes", "Chores", "Other Changes"}, titles)
+	assert.Len(r.Sections[0].Entries, 2)
+	assert.Equal(c1, r.Sections[0].Entries[0].Commit)
+	assert.Equal("api", r.Sections[0].Entries[0].Scope)
+	assert.Equal(c5, r.Sections[0].Entries[1].Commit)
+	assert.Len(r.BreakingChanges, 1)
+	assert.Equal("v1 endpoints are removed", r.BreakingChanges[0].BreakingChange)
+	assert.Equal("https://github.com/owner/repo", r.RepositoryURL)
+	assert.Equal([]IssueReference{
+		{Ref: "#7", ID: "7", URL: "https://github.com/owner/repo/issues/7"},
+		{Ref: "#8", ID: "8", URL: "https://github.com/owner/repo/issues/8"},
+	}, r.Issues)
+	assert.Equal(r.Issues, r.Sections[1].Entries[0].Issues)
+
+	out := new(strings.Builder)
+	assert.NoError(RenderChangelog(out, utils.NewTemplate(DefaultChangelogTemplate), []Release{*r}))
+	assert.Equal(`## 2.0.0 (2024-03-01)
+
+### ⚠ BREAKING CHANGES
+
+* v1 endpoints are removed
+
+### Features
+
+* **api:** add endpoint (1111111)
+* drop v1 API (5555555)
+
+### Bug Fixes
+
+* handle empty body (#7) (2222222)
+
+### Chores
+
+* tidy (6666666)
+
+### Other Changes
+
+* update the README (4444444)
+`, out.String())
+}
+
+func TestReleaseTag(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	when := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "fix: handle empty body"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "Revert \"fix: handle empty body\"\n\nThis reverts commit 1111111111."}
+	c3 := git.Commit{Hash: git.Hash("3333333333"), Message: "perf: cache the parser"}
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", newError("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("v1.2.1").Times(1).Return([]git.Tag{{Name: "v1.2.1", Tagger: git.Signature{When: when}}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.2.1^").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "v1.2.1").Times(1).Return([]git.Commit{c3, c2, c1}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	r, err := strategy.Release("", "v1.2.1")
+	assert.NoError(err)
+	assert.Equal("1.2.1", r.Version.String())
+	assert.Equal("v1.2.1", r.Tag.Name)
+	assert.Equal("v1.2.0", r.PreviousTag.Name)
+	assert.Equal(when, r.Date)
+	assert.Equal([]ChangelogSection{
+		{Type: "perf", Title: "Performance Improvements", Entries: []ChangelogEntry{{ConventionalCommit: c3.Conventional(), Commit: c3}}},
+	}, r.Sections)
+}
+
+func TestReleaseWithRevertOfSplitCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", newError("no remote"))
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("2222222222"), Message: "Revert \"Squash feature (#12)\"\n\nThis reverts commit 1111111111."},
+		{Hash: git.Hash("1111111111"), Message: "Squash feature (#12)\n\n* feat!: a\n\n* fix: b"},
+	}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.SplitCommitBodies = true
+	r, err := strategy.Release("", "")
+	assert.NoError(err)
+	assert.Equal("1.2.0", r.Version.String())
+	assert.Empty(r.Sections)
+	assert.Empty(r.BreakingChanges)
+}
+
+func TestReleases(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "feat: initial"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "fix: bug"}
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", newError("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{{Name: "v0.1.1"}}, nil)
+	gitRepo.EXPECT().GetTags().Times(1).Return([]git.Tag{{Name: "v0.1.1"}, {Name: "latest"}, {Name: "v0.1.0"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v0.1.1^").Times(2).Return(git.Tag{Name: "v0.1.0"}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v0.1.0^").Times(1).Return(git.Tag{}, newError("no tag"))
+	gitRepo.EXPECT().GetCommits("v0.1.0", "v0.1.1").Times(2).Return([]git.Commit{c2}, nil)
+	gitRepo.EXPECT().GetCommits("", "v0.1.0").Times(1).Return([]git.Commit{c1}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	releases, err := strategy.Releases()
+	assert.NoError(err)
+	assert.Len(releases, 2)
+	assert.Equal("0.1.1", releases[0].Version.String())
+	assert.Equal("Bug Fixes", releases[0].Sections[0].Title)
+	assert.Equal("0.1.0", releases[1].Version.String())
+	assert.Nil(releases[1].PreviousTag)
+	assert.Equal("Features", releases[1].Sections[0].Title)
+
+	out := new(strings.Builder)
+	assert.NoError(RenderChangelog(out, utils.NewTemplate("## {{.Version}}\n"), releases))
+	assert.Equal("## 0.1.1\n\n## 0.1.0\n", out.String())
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+var (
+	// squashedItemRegex matches a line item of a squash merge commit body such as "* feat: add option"
+	/* const */ squashedItemRegex = regexp.MustCompile(`^\s*[*-]\s+(.+)$`)
+)
+
+// newContext returns a new Context for the CI provider of the strategy whose changes are split according to the strategy
+func (o *BumpStrategy) newContext(branch string, lastVersion *Version, lastTag *git.Tag, commits []git.Commit) *Context {
+	context := NewContext(branch, lastVersion, lastTag, commits)
+	context.CI = o.CI
+	if o.SplitCommitBodies {
+		context.Changes = o.splitCommits(commits)
+	}
+	return context
+}
+
+// splitCommits splits each commit in its changes, see splitCommit
+func (o *BumpStrategy) splitCommits(commits []git.Commit) []git.Commit {
+	var changes []git.Commit
+	for _, commit := range commits {
+		changes = append(changes, o.splitCommit(commit)...)
+	}
+	return changes
+}
+
+// splitCommit splits a squash merge commit in one change per line item of its body that is a valid header for the convention.
+// The text following a line item until the next one is part of its change.
+// The first change is the commit header with the text before the first line item.
+// Each change keeps the hash, author and committer of the commit.
+func (o *BumpStrategy) splitCommit(commit git.Commit) []git.Commit {
+	var messages []string
+	var sb strings.Builder
+	for _, line := range strings.Split(commit.Message, "\n") {
+		if m := squashedItemRegex.FindStringSubmatch(line); m != nil && o.parseMessage(m[1]).Valid {
+			messages = append(messages, strings.TrimSpace(sb.String()))
+			sb.Reset()
+			line = m[1]
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if len(messages) == 0 {
+		return []git.Commit{commit}
+	}
+	messages = append(messages, strings.TrimSpace(sb.String()))
+
+	changes := make([]git.Commit, len(messages))
+	for i, message := range messages {
+		changes[i] = commit
+		changes[i].Message = message
+	}
+	return changes
+}
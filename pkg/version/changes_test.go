@@ -0,0 +1,109 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestSplitCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		message  string
+		expected []string
+	}{
+		{"fix: typo", []string{"fix: typo"}},
+		{"fix: typo\n\n* not a conventional item\n* another one", []string{"fix: typo\n\n* not a conventional item\n* another one"}},
+		{
+			"Feature/squash (#12)\n\n* feat: a\n\n* fix: b",
+			[]string{"Feature/squash (#12)", "feat: a", "fix: b"},
+		},
+		{
+			"feat: squash (#12)\n\nSummary\n\n- feat(api): a\n  details of a\n\n- refactor!: b\n\nBREAKING CHANGE: b is gone\n\nCo-authored-by: Jane <jane@example.com>",
+			[]string{"feat: squash (#12)\n\nSummary", "feat(api): a\n  details of a", "refactor!: b\n\nBREAKING CHANGE: b is gone\n\nCo-authored-by: Jane <jane@example.com>"},
+		},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			commit := git.Commit{Hash: git.Hash("1234567890"), Author: git.Signature{Name: "Jane"}, Message: tc.message}
+			changes := NewConventionalCommitBumpStrategy(nil).splitCommit(commit)
+			messages := make([]string, len(changes))
+			for i, change := range changes {
+				assert.Equal(commit.Hash, change.Hash)
+				assert.Equal(commit.Author, change.Author)
+				messages[i] = change.Message
+			}
+			assert.Equal(tc.expected, messages)
+		})
+	}
+}
+
+func TestBumpVersionStrategyAutoWithSplitCommitBodies(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		split                 bool
+		buildMetadataTemplate string
+		expected              string
+	}{
+		{false, "", "1.1.1"},
+		{true, "", "1.2.0"},
+		{true, "{{.Commits | len}}.{{.Changes | len}}", "1.1.0+1.3"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+			gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{
+				{Hash: git.Hash("1234567890"), Message: "Squash feature (#12)\n\n* feat: a\n\n* fix: b"},
+			}, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+			gitRepo.EXPECT().GetRemoteURL().Times(1).Return("git@github.com:owner/repo.git", nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.SplitCommitBodies = tc.split
+			strategy.BumpStrategies = []BumpBranchesStrategy{*NewBuildBumpBranchesStrategy(".*", tc.buildMetadataTemplate)}
+			version, err := strategy.Bump()
+
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
+
+func TestBumpVersionStrategyAutoWithRevertOfSplitCommit(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("abcdef1234"), Message: "Revert \"Squash feature (#12)\"\n\nThis reverts commit 1234567890."},
+		{Hash: git.Hash("1234567890"), Message: "Squash feature (#12)\n\n* feat!: a\n\n* fix: b"},
+	}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.SplitCommitBodies = true
+	version, err := strategy.Bump()
+
+	assert.NoError(err)
+	assert.Equal("1.1.0", version.String())
+}
@@ -5,6 +5,7 @@ import (
 	"text/template"
 
 	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
 	"github.com/arnaud-deprez/gsemver/pkg/git"
 )
 
@@ -15,6 +16,7 @@ func NewContext(branch string, lastVersion *Version, lastTag *git.Tag, commits [
 		LastVersion: lastVersion,
 		LastTag:     lastTag,
 		Commits:     commits,
+		Changes:     commits,
 	}
 }
 
@@ -22,13 +24,26 @@ func NewContext(branch string, lastVersion *Version, lastTag *git.Tag, commits [
 // This context is also used as template data.
 type Context struct {
 	// Branch is the current branch name
-	Branch string
+	Branch string `json:"branch"`
 	// LastVersion is a semver version representation of the last git tag
-	LastVersion *Version
+	LastVersion *Version `json:"lastVersion"`
 	// LastTag is the last git tag
-	LastTag *git.Tag
+	LastTag *git.Tag `json:"lastTag"`
 	// Commits is the list of commits from the previous tag until now
-	Commits []git.Commit
+	Commits []git.Commit `json:"commits"`
+	// Changes is the list of changes used to detect the bump.
+	// It is the list of commits unless BumpStrategy.SplitCommitBodies is enabled, in which case
+	// the line items of squash merge commit bodies are distinct changes.
+	Changes []git.Commit `json:"changes"`
+	// CI is the CI provider running the build, see BumpStrategy.CI. Its name is empty outside of a supported CI provider.
+	CI ci.Provider `json:"ci"`
+	// ReleaseAs is the version forced by a Release-As footer of the commits, nil if the version is not forced
+	ReleaseAs *ReleaseAs `json:"releaseAs,omitempty"`
+	// Issues are the issues referenced by the changes, from the oldest to the most recent
+	Issues []IssueReference `json:"issues,omitempty"`
+	// Dirty is true if the version is computed for HEAD and the working tree has uncommitted changes.
+	// It is only detected if the dirty policy of the matching strategy is not ignore.
+	Dirty bool `json:"dirty"`
 }
 
 // EvalTemplate evaluates the given template against the current context
@@ -0,0 +1,27 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/ci"
+)
+
+func TestContextCI(t *testing.T) {
+	assert := assert.New(t)
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("CI_COMMIT_BRANCH", "feature/one")
+
+	// the CI provider is not detected from the environment
+	context := NewContext("feature/one", &zeroVersion, nil, nil)
+	assert.Equal(ci.Provider{}, context.CI)
+
+	// it is the one of the strategy
+	strategy := NewConventionalCommitBumpStrategy(nil)
+	strategy.CI = ci.Provider{Name: ci.GitLab, Branch: "feature/one"}
+	context = strategy.newContext("feature/one", &zeroVersion, nil, nil)
+	assert.Equal(ci.Provider{Name: ci.GitLab, Branch: "feature/one"}, context.CI)
+	assert.Equal("gitlab", context.EvalTemplate(utils.NewTemplate("{{.CI.Name}}")))
+}
@@ -0,0 +1,185 @@
+package version
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// ConventionalCommits is the name of the https://www.conventionalcommits.org convention
+	ConventionalCommits = "conventional"
+	// Angular is the name of the Angular convention: only feat, fix and perf commits trigger a release
+	Angular = "angular"
+	// Gitmoji is the name of the https://gitmoji.dev convention
+	Gitmoji = "gitmoji"
+	// ESLint is the name of the ESLint convention, eg. "Fix: message"
+	ESLint = "eslint"
+	// Atom is the name of the Atom editor convention, eg. ":bug: message"
+	Atom = "atom"
+	// JQuery is the name of the jQuery convention, eg. "Component: message"
+	JQuery = "jquery"
+
+	// DefaultConvention defines the default convention
+	DefaultConvention = ConventionalCommits
+)
+
+var (
+	// conventions is the registry of conventions by name
+	conventions = map[string]*Convention{}
+)
+
+func init() {
+	for _, c := range []Convention{
+		{
+			Name:          ConventionalCommits,
+			MajorPattern:  DefaultMajorPattern,
+			MinorPattern:  DefaultMinorPattern,
+			CommitPattern: DefaultCommitPattern,
+			HeaderPattern: git.ConventionalHeaderPattern,
+		},
+		{
+			Name:          Angular,
+			MajorPattern:  `(?m)^BREAKING CHANGE:.+$`,
+			MinorPattern:  `^feat(?:\(.+\))?: .+`,
+			PatchPattern:  `^(?:fix|perf)(?:\(.+\))?: .+`,
+			CommitPattern: `^(?:build|ci|docs|feat|fix|perf|refactor|style|test)(?:\(.+\))?: .+`,
+			HeaderPattern: git.ConventionalHeaderPattern,
+		},
+		{
+			Name:          Gitmoji,
+			MajorPattern:  `^(?::boom:|\x{1F4A5})`,
+			MinorPattern:  `^(?::sparkles:|\x{2728})`,
+			PatchPattern:  `^(?::bug:|\x{1F41B}|:ambulance:|\x{1F691}|:lock:|\x{1F512}|:zap:|\x{26A1})`,
+			CommitPattern: `^(?::[\w+-]+:|\p{So}\x{FE0F}?)(?: ?\([^()\r\n]+\))?:? .+`,
+			HeaderPattern: `^(?P<type>:[\w+-]+:|\p{So}\x{FE0F}?)(?: ?\((?P<scope>[^()\r\n]*)\))?:? (?P<description>.+)$`,
+		},
+		{
+			Name:          ESLint,
+			MajorPattern:  `^Breaking: .+`,
+			MinorPattern:  `^(?:New|Update): .+`,
+			PatchPattern:  `^(?:Fix|Upgrade): .+`,
+			CommitPattern: `^(?:Fix|Update|New|Breaking|Docs|Build|Upgrade|Chore): .+`,
+			HeaderPattern: `^(?P<type>[A-Z][a-z]+): (?P<description>.+)$`,
+		},
+		{
+			Name:          Atom,
+			PatchPattern:  `^(?::bug:|:racehorse:|:lock:|:non-potable_water:|:apple:|:penguin:|:checkered_flag:) .+`,
+			CommitPattern: `^:[\w+-]+: .+`,
+			HeaderPattern: `^(?P<type>:[\w+-]+:) (?P<description>.+)$`,
+		},
+		{
+			Name:          JQuery,
+			CommitPattern: `^[\w./-]+: .+`,
+			HeaderPattern: `^(?P<scope>[\w./-]+): (?P<description>.+)$`,
+		},
+	} {
+		if err := RegisterConvention(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Convention defines how the commit messages are interpreted to compute the bump.
+// An empty pattern never matches, except PatchPattern: if it is empty, any commit that is not a major or
+// a minor change is a patch change, otherwise the commits matching none of the patterns do not trigger any bump.
+type Convention struct {
+	// Name is the name used to select the convention
+	Name string
+	// MajorPattern is the regex used to detect if a commit contains a breaking/major change
+	MajorPattern string
+	// MinorPattern is the regex used to detect if a commit contains a minor change
+	MinorPattern string
+	// PatchPattern is the regex used to detect if a commit contains a patch change
+	PatchPattern string
+	// CommitPattern is the regex a commit header must match to follow the convention
+	CommitPattern string
+	// HeaderPattern is the regex used to parse a commit header, see git.ParseCommitMessage
+	HeaderPattern string
+	// headerRegex is the compiled HeaderPattern
+	headerRegex *regexp.Regexp
+}
+
+// RegisterConvention adds a convention to the registry or replaces the convention with the same name
+func RegisterConvention(c Convention) error {
+	if c.Name == "" {
+		return newError("Convention requires a name")
+	}
+	for _, pattern := range []string{c.MajorPattern, c.MinorPattern, c.PatchPattern, c.CommitPattern} {
+		if _, err := compilePattern(pattern); err != nil {
+			return newErrorC(err, "Convention %q has an invalid pattern", c.Name)
+		}
+	}
+	headerRegex, err := regexp.Compile(c.HeaderPattern)
+	if err != nil || c.HeaderPattern == "" {
+		return newErrorC(err, "Convention %q has an invalid header pattern", c.Name)
+	}
+	c.headerRegex = headerRegex
+	conventions[strings.ToLower(c.Name)] = &c
+	return nil
+}
+
+// GetConvention returns the registered convention with the name (case insensitive)
+func GetConvention(name string) (Convention, error) {
+	if c, ok := conventions[strings.ToLower(name)]; ok {
+		return *c, nil
+	}
+	return Convention{}, newError("Unknown convention %q, expected one of %s", name, strings.Join(ConventionNames(), ", "))
+}
+
+// ConventionNames returns the sorted names of the registered conventions
+func ConventionNames() []string {
+	names := make([]string, 0, len(conventions))
+	for name := range conventions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse parses a commit message with the header pattern of the convention
+func (c Convention) Parse(message string) git.ConventionalCommit {
+	return git.ParseCommitMessage(message, c.headerRegex)
+}
+
+/*
+NewBumpStrategyWithConvention creates a BumpStrategy for a named convention.
+
+It uses the patterns of the convention and the same bump branches strategies as NewConventionalCommitBumpStrategy.
+*/
+func NewBumpStrategyWithConvention(name string, gitRepo GitRepo) (*BumpStrategy, error) {
+	c, err := GetConvention(name)
+	if err != nil {
+		return nil, err
+	}
+	s := NewConventionalCommitBumpStrategy(gitRepo)
+	s.Convention = c.Name
+	s.MajorPattern, _ = compilePattern(c.MajorPattern)
+	s.MinorPattern, _ = compilePattern(c.MinorPattern)
+	s.PatchPattern, _ = compilePattern(c.PatchPattern)
+	s.CommitPattern, _ = compilePattern(c.CommitPattern)
+	return s, nil
+}
+
+// parseMessage parses a commit message with the convention of the strategy, Conventional Commits by default
+func (o *BumpStrategy) parseMessage(message string) git.ConventionalCommit {
+	if c, ok := conventions[strings.ToLower(o.Convention)]; ok {
+		return c.Parse(message)
+	}
+	return git.ParseConventionalCommit(message)
+}
+
+// compilePattern compiles a pattern, an empty pattern returns nil
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
+
+// matchPattern returns true if the pattern is defined and matches the message
+func matchPattern(pattern *regexp.Regexp, message string) bool {
+	return pattern != nil && pattern.MatchString(message)
+}
@@ -0,0 +1,109 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+func TestConventionBumpStrategyType(t *testing.T) {
+	assert := assert.New(t)
+
+	// none means the commits do not trigger any bump
+	const none = BumpStrategyType(-1)
+
+	testData := []struct {
+		convention string
+		message    string
+		expected   BumpStrategyType
+	}{
+		{ConventionalCommits, "feat(api)!: drop v1", MAJOR},
+		{ConventionalCommits, "feat: add option", MINOR},
+		{ConventionalCommits, "docs: typo", PATCH},
+		{ConventionalCommits, "update README.md", PATCH},
+		{Angular, "feat(core): add option\n\nBREAKING CHANGE: option replaces flag", MAJOR},
+		{Angular, "feat(core): add option", MINOR},
+		{Angular, "perf(core): faster", PATCH},
+		{Angular, "docs: typo", none},
+		{Gitmoji, ":boom: drop v1", MAJOR},
+		{Gitmoji, "\U0001F4A5 drop v1", MAJOR},
+		{Gitmoji, ":sparkles: add option", MINOR},
+		{Gitmoji, "✨ (api): add option", MINOR},
+		{Gitmoji, ":bug: fix crash", PATCH},
+		{Gitmoji, "\U0001F691️ hotfix", PATCH},
+		{Gitmoji, ":memo: update docs", none},
+		{Gitmoji, ":memo: update docs\n\nBREAKING CHANGE: docs moved", MAJOR},
+		{ESLint, "Breaking: drop node 10", MAJOR},
+		{ESLint, "New: add rule", MINOR},
+		{ESLint, "Update: rule options", MINOR},
+		{ESLint, "Fix: crash (fixes #1)", PATCH},
+		{ESLint, "Docs: typo", none},
+		{Atom, ":bug: fix crash", PATCH},
+		{Atom, ":memo: update docs", none},
+		{Atom, ":art: format\n\nBREAKING CHANGE: format changed", MAJOR},
+		{JQuery, "Ajax: fix timeout", PATCH},
+		{JQuery, "Core: drop IE\n\nBREAKING CHANGE: IE is not supported", MAJOR},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			strategy, err := NewBumpStrategyWithConvention(tc.convention, nil)
+			assert.NoError(err)
+
+			context := NewContext("main", &Version{Major: 1}, &git.Tag{Name: "v1.0.0"}, []git.Commit{{Hash: "1234567890", Message: tc.message}})
+			actual, ok := strategy.computeBumpStrategyType(context)
+			if tc.expected == none {
+				assert.False(ok)
+			} else {
+				assert.True(ok)
+				assert.Equal(tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConventionParse(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := GetConvention("GITMOJI")
+	assert.NoError(err)
+	assert.Equal(git.ConventionalCommit{Type: ":sparkles:", Scope: "api", Description: "add option", Body: "details", Valid: true}, c.Parse(":sparkles: (api): add option\n\ndetails"))
+
+	c, err = GetConvention(JQuery)
+	assert.NoError(err)
+	assert.Equal(git.ConventionalCommit{Scope: "Ajax", Description: "fix timeout", Footers: []git.Footer{{Token: "Fixes", Value: "gh-123"}}, Valid: true}, c.Parse("Ajax: fix timeout\n\nFixes: gh-123"))
+}
+
+func TestConventionLint(t *testing.T) {
+	assert := assert.New(t)
+
+	strategy, err := NewBumpStrategyWithConvention(Gitmoji, nil)
+	assert.NoError(err)
+	assert.Empty(strategy.LintMessage(":sparkles: add option"))
+	assert.Empty(strategy.LintMessage("✨ add option"))
+	assert.Len(strategy.LintMessage("feat: add option"), 1)
+}
+
+func TestGetUnknownConvention(t *testing.T) {
+	_, err := NewBumpStrategyWithConvention("foo", nil)
+	assert.EqualError(t, err, `Unknown convention "foo", expected one of angular, atom, conventional, eslint, gitmoji, jquery`)
+}
+
+func TestRegisterConvention(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.EqualError(RegisterConvention(Convention{}), "Convention requires a name")
+	assert.Error(RegisterConvention(Convention{Name: "broken", MajorPattern: "("}))
+	assert.Error(RegisterConvention(Convention{Name: "broken"}))
+
+	assert.NoError(RegisterConvention(Convention{Name: "custom", MinorPattern: `^\[feature\]`, PatchPattern: `^\[fix\]`, HeaderPattern: `^\[(?P<type>\w+)\] (?P<description>.+)$`}))
+	defer delete(conventions, "custom")
+
+	strategy, err := NewBumpStrategyWithConvention("custom", nil)
+	assert.NoError(err)
+	assert.Nil(strategy.MajorPattern)
+	assert.Equal("feature", strategy.parseMessage("[feature] add option").Type)
+}
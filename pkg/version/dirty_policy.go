@@ -0,0 +1,84 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DirtyPolicy defines how the version is computed when the working tree has uncommitted changes
+type DirtyPolicy int
+
+const (
+	// DirtyIgnore computes the version as if the working tree was clean
+	DirtyIgnore DirtyPolicy = iota
+	// DirtyAppend appends a dirty identifier to the build metadata of the version, eg. 1.2.0+dirty
+	DirtyAppend
+	// DirtyFail fails to compute the version
+	DirtyFail
+)
+
+const (
+	// DirtyBuildIdentifier is the build metadata identifier appended to the version of a dirty working tree by DirtyAppend
+	DirtyBuildIdentifier = "dirty"
+)
+
+var dirtyPolicyToString = []string{"ignore", "append", "fail"}
+
+// ParseDirtyPolicy converts string value to DirtyPolicy. An empty value is DirtyIgnore.
+func ParseDirtyPolicy(value string) (DirtyPolicy, error) {
+	switch strings.ToLower(value) {
+	case "", "ignore":
+		return DirtyIgnore, nil
+	case "append":
+		return DirtyAppend, nil
+	case "fail":
+		return DirtyFail, nil
+	default:
+		return DirtyIgnore, fmt.Errorf("unknown dirty policy %q, expected one of %s", value, strings.Join(dirtyPolicyToString, ", "))
+	}
+}
+
+func (p DirtyPolicy) String() string {
+	return dirtyPolicyToString[p]
+}
+
+// UnmarshalJSON implements unmarshall for encoding/json
+func (p *DirtyPolicy) UnmarshalJSON(bs []byte) error {
+	var s string
+	if err := json.Unmarshal(bs, &s); err != nil {
+		return err
+	}
+	policy, err := ParseDirtyPolicy(s)
+	if err != nil {
+		return err
+	}
+	*p = policy
+	return nil
+}
+
+// MarshalJSON implements marshall for encoding/json
+func (p DirtyPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// applyDirtyPolicy applies the policy to the version bumper when the working tree of the context is dirty
+func (p DirtyPolicy) applyDirtyPolicy(bumper versionBumper, ctx *Context) (versionBumper, error) {
+	if !ctx.Dirty {
+		return bumper, nil
+	}
+	switch p {
+	case DirtyFail:
+		return nil, newError("Working tree has uncommitted changes, commit or stash them before computing the version")
+	case DirtyAppend:
+		return func(v Version) Version {
+			next := bumper(v)
+			if next.BuildMetadata == "" {
+				return next.WithBuildMetadata(DirtyBuildIdentifier)
+			}
+			return next.WithBuildMetadata(next.BuildMetadata + "." + DirtyBuildIdentifier)
+		}, nil
+	default:
+		return bumper, nil
+	}
+}
@@ -0,0 +1,155 @@
+package version
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestParseDirtyPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		value    string
+		expected DirtyPolicy
+		err      string
+	}{
+		{"", DirtyIgnore, ""},
+		{"ignore", DirtyIgnore, ""},
+		{"append", DirtyAppend, ""},
+		{"FAIL", DirtyFail, ""},
+		{"warn", DirtyIgnore, `unknown dirty policy "warn", expected one of ignore, append, fail`},
+	}
+
+	for _, tc := range testData {
+		policy, err := ParseDirtyPolicy(tc.value)
+		if tc.err != "" {
+			assert.EqualError(err, tc.err)
+			continue
+		}
+		assert.NoError(err)
+		assert.Equal(tc.expected, policy)
+	}
+}
+
+func TestDirtyPolicyEncodingJson(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, it := range []DirtyPolicy{DirtyIgnore, DirtyAppend, DirtyFail} {
+		out, err := json.Marshal(it)
+		assert.NoError(err)
+		assert.Equal(fmt.Sprintf("%q", it), string(out))
+
+		var value DirtyPolicy
+		assert.NoError(json.Unmarshal(out, &value))
+		assert.Equal(it, value)
+	}
+
+	var value DirtyPolicy
+	assert.Error(json.Unmarshal([]byte(`"warn"`), &value))
+
+	// the default policy is omitted
+	out, err := json.Marshal(NewDefaultBumpBranchesStrategy("main"))
+	assert.NoError(err)
+	assert.NotContains(string(out), "dirtyPolicy")
+}
+
+func TestBumpVersionStrategyDirty(t *testing.T) {
+	testData := []struct {
+		name     string
+		strategy *BumpBranchesStrategy
+		dirty    bool
+		expected string
+		err      string
+	}{
+		{"clean", newDirtyBumpBranchesStrategy(DirtyFail, ""), false, "1.1.0", ""},
+		{"ignore", newDirtyBumpBranchesStrategy(DirtyIgnore, ""), true, "1.1.0", ""},
+		{"append", newDirtyBumpBranchesStrategy(DirtyAppend, ""), true, "1.1.0+dirty", ""},
+		{"append to build metadata", newDirtyBumpBranchesStrategy(DirtyAppend, "{{.Commits | len}}"), true, "1.0.0+1.dirty", ""},
+		{"template", newDirtyBumpBranchesStrategy(DirtyAppend, "{{if .Dirty}}local{{else}}ci{{end}}"), true, "1.0.0+local.dirty", ""},
+		{"fail", newDirtyBumpBranchesStrategy(DirtyFail, ""), true, "", "Working tree has uncommitted changes, commit or stash them before computing the version"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// mock
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			if tc.strategy.DirtyPolicy != DirtyIgnore {
+				gitRepo.EXPECT().IsDirty().Times(1).Return(tc.dirty, nil)
+			}
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+			gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{
+				{Hash: git.Hash("1234567890"), Message: "feat: add offline mode"},
+			}, nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.BumpStrategies = []BumpBranchesStrategy{*tc.strategy}
+			version, err := strategy.Bump()
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
+
+func TestBumpVersionStrategyDirtyError(t *testing.T) {
+	assert := assert.New(t)
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().IsDirty().Times(1).Return(false, errors.New("fatal: not a git repository"))
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{{Message: "fix: typo"}}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.BumpStrategies = []BumpBranchesStrategy{*newDirtyBumpBranchesStrategy(DirtyFail, "")}
+	_, err := strategy.Bump()
+	assert.EqualError(err, "Cannot get the status of the working tree caused by: fatal: not a git repository")
+}
+
+func TestBumpVersionStrategyDirtyIgnoreWithoutWorkTree(t *testing.T) {
+	assert := assert.New(t)
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().IsDirty().Times(0).Return(false, errors.New("fatal: this operation must be run in a work tree"))
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{{Message: "fix: typo"}}, nil)
+
+	// the working tree is not queried with the ignore policy of the default strategy
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	version, err := strategy.Bump()
+	assert.NoError(err)
+	assert.Equal("1.0.1", version.String())
+}
+
+// newDirtyBumpBranchesStrategy creates a strategy for all the branches with a dirty policy
+func newDirtyBumpBranchesStrategy(policy DirtyPolicy, buildMetadataTemplate string) *BumpBranchesStrategy {
+	s := NewBumpAllBranchesStrategy(AUTO, false, "", false, buildMetadataTemplate)
+	s.DirtyPolicy = policy
+	return s
+}
@@ -0,0 +1,46 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+)
+
+// FetchOptions configures how the tags are fetched before computing a version
+type FetchOptions struct {
+	// Skip disables the fetch, only the local tags are used, eg. in a repository without remote or offline
+	Skip bool `json:"skip,omitempty"`
+	// Remote is the name of the remote the tags are fetched from. If empty, it is origin.
+	Remote string `json:"remote,omitempty"`
+	// Refspec is the refspec fetched with the tags. If empty, it is the one configured for the remote.
+	Refspec string `json:"refspec,omitempty"`
+	// IgnoreErrors turns a fetch failure into a warning, the version is then computed with the local tags
+	IgnoreErrors bool `json:"ignoreErrors,omitempty"`
+}
+
+// GoString makes FetchOptions satisfy the GoStringer interface.
+func (o FetchOptions) GoString() string {
+	return fmt.Sprintf("version.FetchOptions{Skip: %v, Remote: %q, Refspec: %q, IgnoreErrors: %v}", o.Skip, o.Remote, o.Refspec, o.IgnoreErrors)
+}
+
+// fetchTags makes sure we have the tags of the remote according to the fetch options
+func (o *BumpStrategy) fetchTags() error {
+	if o.Fetch.Skip {
+		log.Debug("BumpStrategy: skip fetching the tags, only the local tags are used")
+		return nil
+	}
+	var err error
+	if o.Fetch.Remote == "" && o.Fetch.Refspec == "" {
+		err = o.gitRepo.FetchTags()
+	} else {
+		err = o.gitRepo.FetchTagsFrom(o.Fetch.Remote, o.Fetch.Refspec)
+	}
+	if err != nil && o.Fetch.IgnoreErrors {
+		log.Warn("%v", newErrorC(err, "Cannot fetch tags, only the local tags are used"))
+		return nil
+	}
+	if err != nil {
+		return newErrorC(err, "Cannot fetch tags")
+	}
+	return nil
+}
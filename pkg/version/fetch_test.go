@@ -0,0 +1,69 @@
+package version
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestBumpFetchOptions(t *testing.T) {
+	fetchErr := errors.New("fatal: 'origin' does not appear to be a git repository")
+	testData := []struct {
+		name     string
+		fetch    FetchOptions
+		expect   func(gitRepo *mock_version.MockGitRepo)
+		expected string
+		err      string
+	}{
+		{"default", FetchOptions{}, func(gitRepo *mock_version.MockGitRepo) {
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+		}, "1.1.0", ""},
+		{"skip", FetchOptions{Skip: true, Remote: "upstream"}, func(_ *mock_version.MockGitRepo) {}, "1.1.0", ""},
+		{"remote", FetchOptions{Remote: "upstream"}, func(gitRepo *mock_version.MockGitRepo) {
+			gitRepo.EXPECT().FetchTagsFrom("upstream", "").Times(1).Return(nil)
+		}, "1.1.0", ""},
+		{"refspec", FetchOptions{Refspec: "+refs/heads/main:refs/remotes/origin/main"}, func(gitRepo *mock_version.MockGitRepo) {
+			gitRepo.EXPECT().FetchTagsFrom("", "+refs/heads/main:refs/remotes/origin/main").Times(1).Return(nil)
+		}, "1.1.0", ""},
+		{"ignore errors", FetchOptions{IgnoreErrors: true}, func(gitRepo *mock_version.MockGitRepo) {
+			gitRepo.EXPECT().FetchTags().Times(1).Return(fetchErr)
+		}, "1.1.0", ""},
+		{"error", FetchOptions{}, func(gitRepo *mock_version.MockGitRepo) {
+			gitRepo.EXPECT().FetchTags().Times(1).Return(fetchErr)
+		}, "", "Cannot fetch tags caused by: fatal: 'origin' does not appear to be a git repository"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			// mock
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			tc.expect(gitRepo)
+			if tc.err == "" {
+				gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+				gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+				gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{
+					{Hash: git.Hash("1234567890"), Message: "feat: add offline mode"},
+				}, nil)
+			}
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.Fetch = tc.fetch
+			version, err := strategy.Bump()
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
@@ -1,22 +1,58 @@
 package version
 
 import (
+	"errors"
+
 	"github.com/arnaud-deprez/gsemver/pkg/git"
 )
 
+// ErrTagAlreadyExists is returned by GitRepo.PushTag when the remote repository rejects a tag because it already has
+// a different tag with the same name
+var ErrTagAlreadyExists = errors.New("tag already exists in the remote repository")
+
+// ErrShallowClone is returned by GitRepo.GetLastRelativeTag when no tag is found in a shallow clone, even after
+// fetching more of its history. The version would restart from 0.0.0 otherwise.
+var ErrShallowClone = errors.New("no tag found in the shallow clone, fetch the full history with git fetch --unshallow")
+
 // GitRepo defines common git actions used by gsemver
 //
 //go:generate mockgen -destination mock/git_repo.go github.com/arnaud-deprez/gsemver/pkg/version GitRepo
 type GitRepo interface {
 	// FetchTags fetches the tags from remote
 	FetchTags() error
+	// FetchTagsFrom fetches the tags and the refspec from a remote.
+	// If remote is empty, it is origin. If refspec is empty, it is the one configured for the remote.
+	FetchTagsFrom(remote string, refspec string) error
 	// GetCommits return the list of commits between 2 revisions.
 	// If no revision is provided, it does from beginning to HEAD
 	GetCommits(from string, to string) ([]git.Commit, error)
+	// GetFirstParentCommits return the list of commits between 2 revisions following only the first parent of merge commits.
+	// If no revision is provided, it does from beginning to HEAD
+	GetFirstParentCommits(from string, to string) ([]git.Commit, error)
 	// CountCommits counts the number of commits between 2 revisions.
 	CountCommits(from string, to string) (int, error)
-	// GetLastRelativeTag gives the last ancestor tag from HEAD
+	// GetLastRelativeTag gives the last ancestor tag from HEAD.
+	// In a shallow clone, it fetches more history until a tag is found and returns an error wrapping ErrShallowClone
+	// if there is still none.
 	GetLastRelativeTag(rev string) (git.Tag, error)
+	// GetTags gives all the tags of the repository
+	GetTags() ([]git.Tag, error)
+	// GetTagsPointingAt gives the tags pointing at a revision
+	GetTagsPointingAt(rev string) ([]git.Tag, error)
 	// GetCurrentBranch gives the current branch from HEAD
 	GetCurrentBranch() (string, error)
+	// GetRemoteURL gives the URL of the remote repository
+	GetRemoteURL() (string, error)
+	// IsDirty tells if the working tree or the index has changes of tracked files that are not committed.
+	// The untracked files are ignored.
+	IsDirty() (bool, error)
+	// Commit commits the changes of the files with the message
+	Commit(message string, paths ...string) error
+	// CreateTag creates an annotated tag on HEAD, signed with the gpg key of the tagger if sign is true
+	CreateTag(name string, message string, sign bool) error
+	// DeleteTag deletes a local tag
+	DeleteTag(name string) error
+	// PushTag pushes a tag to the remote repository atomically.
+	// It returns an error wrapping ErrTagAlreadyExists if the remote repository has a different tag with the same name.
+	PushTag(name string) error
 }
@@ -0,0 +1,86 @@
+package version
+
+import (
+	"errors"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+// HistoryEntry represents the version computed for a commit of the history
+type HistoryEntry struct {
+	// Commit is the commit of the history
+	Commit git.Commit `json:"commit"`
+	// Version is the version computed at this commit
+	Version Version `json:"version"`
+	// Tags are the tags actually pointing at this commit
+	Tags []git.Tag `json:"tags"`
+}
+
+// TagsMatch returns true if the entry has no version tag or if one of its version tags matches the computed version.
+func (e *HistoryEntry) TagsMatch() bool {
+	hasVersionTag := false
+	for _, tag := range e.Tags {
+		v, err := NewVersion(extractVersionFromTag(tag.Name))
+		if err != nil {
+			continue
+		}
+		hasVersionTag = true
+		if v.String() == e.Version.String() {
+			return true
+		}
+	}
+	return !hasVersionTag
+}
+
+// History computes the version for every commit of the first-parent history from a revision (excluded) to HEAD.
+// The entries are ordered from the oldest to the most recent commit.
+//
+// The version of a commit is computed with the last tag reachable from its parent,
+// so it shows what would have been computed before any tag was created on this commit.
+func (o *BumpStrategy) History(from string) ([]HistoryEntry, error) {
+	log.Debug("BumpStrategy: history with configuration: %#v", o)
+
+	// Make sure we have the tags
+	if err := o.fetchTags(); err != nil {
+		return nil, err
+	}
+
+	currentBranch, err := o.gitRepo.GetCurrentBranch()
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get current branch name")
+	}
+
+	commits, err := o.gitRepo.GetFirstParentCommits(from, "HEAD")
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get first-parent history")
+	}
+
+	entries := make([]HistoryEntry, len(commits))
+	for i, commit := range commits {
+		rev := commit.Hash.String()
+		lastTag, err := o.gitRepo.GetLastRelativeTag(rev + "^")
+		if errors.Is(err, ErrShallowClone) {
+			return nil, newErrorC(err, "Cannot get last relative tag of %s", rev)
+		}
+		if err != nil {
+			// the root commit has no parent or there is no tag yet
+			log.Debug("%v", newErrorC(err, "Unable to get last relative tag of %s", rev))
+		}
+
+		v, _, err := o.computeVersion(lastTag, rev, currentBranch)
+		if err != nil {
+			return nil, newErrorC(err, "Cannot compute version of %s", rev)
+		}
+
+		tags, err := o.gitRepo.GetTagsPointingAt(rev)
+		if err != nil {
+			return nil, newErrorC(err, "Cannot get tags of %s", rev)
+		}
+
+		// git log gives the most recent commit first
+		entries[len(commits)-1-i] = HistoryEntry{Commit: commit, Version: v, Tags: tags}
+	}
+
+	return entries, nil
+}
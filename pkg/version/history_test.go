@@ -0,0 +1,78 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "feat: add README.md"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "fix(doc): fix documentation"}
+	c3 := git.Commit{Hash: git.Hash("3333333333"), Message: "feat!: breaking"}
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetFirstParentCommits("", "HEAD").Times(1).Return([]git.Commit{c3, c2, c1}, nil)
+	// root commit has no parent
+	gitRepo.EXPECT().GetLastRelativeTag("1111111111^").Times(1).Return(git.Tag{}, newError("no parent"))
+	gitRepo.EXPECT().GetLastRelativeTag("2222222222^").Times(1).Return(git.Tag{Name: "v0.1.0"}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("3333333333^").Times(1).Return(git.Tag{Name: "v0.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("", "1111111111").Times(1).Return([]git.Commit{c1}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.0", "2222222222").Times(1).Return([]git.Commit{c2}, nil)
+	gitRepo.EXPECT().GetCommits("v0.1.0", "3333333333").Times(1).Return([]git.Commit{c3, c2}, nil)
+	gitRepo.EXPECT().GetTagsPointingAt("1111111111").Times(1).Return([]git.Tag{{Name: "v0.1.0"}}, nil)
+	gitRepo.EXPECT().GetTagsPointingAt("2222222222").Times(1).Return([]git.Tag{{Name: "v0.1.2"}}, nil)
+	gitRepo.EXPECT().GetTagsPointingAt("3333333333").Times(1).Return([]git.Tag{}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	entries, err := strategy.History("")
+
+	assert.NoError(err)
+	assert.Len(entries, 3)
+
+	assert.Equal(c1, entries[0].Commit)
+	assert.Equal("0.1.0", entries[0].Version.String())
+	assert.True(entries[0].TagsMatch())
+
+	assert.Equal(c2, entries[1].Commit)
+	assert.Equal("0.1.1", entries[1].Version.String())
+	assert.False(entries[1].TagsMatch())
+
+	assert.Equal(c3, entries[2].Commit)
+	assert.Equal("0.2.0", entries[2].Version.String())
+	assert.True(entries[2].TagsMatch())
+}
+
+func TestHistoryEntryTagsMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		version  Version
+		tags     []git.Tag
+		expected bool
+	}{
+		{Version{Major: 1}, nil, true},
+		{Version{Major: 1}, []git.Tag{{Name: "latest"}}, true},
+		{Version{Major: 1}, []git.Tag{{Name: "v1.0.0"}}, true},
+		{Version{Major: 1}, []git.Tag{{Name: "foo/v1.0.0"}}, true},
+		{Version{Major: 1}, []git.Tag{{Name: "v0.2.0"}, {Name: "v1.0.0"}}, true},
+		{Version{Major: 1}, []git.Tag{{Name: "v0.2.0"}}, false},
+	}
+
+	for _, tc := range testData {
+		e := HistoryEntry{Version: tc.version, Tags: tc.tags}
+		assert.Equal(tc.expected, e.TagsMatch(), "%v with tags %v", tc.version, tc.tags)
+	}
+}
@@ -0,0 +1,159 @@
+package version
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// DefaultIssuePattern defines default regular expression to match GitHub issue references such as #123 or GH-123
+	DefaultIssuePattern = `(?:\B#|\bGH-)(?P<id>\d+)\b`
+)
+
+var (
+	// scpLikeURLRegex matches the scp-like syntax of ssh remote URLs such as git@github.com:owner/repo.git
+	/* const */ scpLikeURLRegex = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):([^/].*)$`)
+)
+
+// IssuePattern defines how the issue references are found in the commit messages and linked
+type IssuePattern struct {
+	// Pattern is the regex matching an issue reference. The issue id is its id named group, or else the whole match.
+	Pattern *regexp.Regexp `json:"pattern"`
+	// URLTemplate is the go template of the link to the issue, it can use the issue ID and Ref and the RepositoryURL.
+	// If nil, the link is the issue page of the repository, eg. https://github.com/owner/repo/issues/123.
+	URLTemplate *template.Template `json:"urlTemplate,omitempty"`
+}
+
+// IssueReference represents an issue referenced by a commit message
+type IssueReference struct {
+	// Ref is the reference as written in the commit message, eg. #123 or JIRA-456
+	Ref string `json:"ref"`
+	// ID is the issue id, eg. 123 or JIRA-456
+	ID string `json:"id"`
+	// URL is the link to the issue, empty if it cannot be resolved
+	URL string `json:"url,omitempty"`
+}
+
+// key identifies the issue whatever the way it is referenced, eg. #123 and GH-123
+func (r IssueReference) key() string {
+	return r.ID + " " + r.URL
+}
+
+// issueReferences finds the issues referenced by a commit message in the order of the patterns
+func (o *BumpStrategy) issueReferences(message string, repositoryURL string) []IssueReference {
+	var ret []IssueReference
+	seen, refs := map[string]bool{}, map[string]bool{}
+	for _, p := range o.IssuePatterns {
+		if p.Pattern == nil {
+			continue
+		}
+		idIndex := p.Pattern.SubexpIndex("id")
+		for _, m := range p.Pattern.FindAllStringSubmatch(message, -1) {
+			ref := IssueReference{Ref: strings.TrimSpace(m[0]), ID: strings.TrimSpace(m[0])}
+			if idIndex > 0 && m[idIndex] != "" {
+				ref.ID = m[idIndex]
+			}
+			// a reference matched by a previous pattern is not matched again, eg. GH-7 by a JIRA pattern
+			if refs[ref.Ref] {
+				continue
+			}
+			refs[ref.Ref] = true
+			ref.URL = p.issueURL(ref, repositoryURL)
+			if !seen[ref.key()] {
+				seen[ref.key()] = true
+				ret = append(ret, ref)
+			}
+		}
+	}
+	return ret
+}
+
+// changesIssues finds the issues referenced by the changes from the oldest to the most recent.
+// The changes are expected from the most recent to the oldest, like git log, and the reverted ones are ignored.
+// The repository URL is only resolved if there is an issue reference.
+func (o *BumpStrategy) changesIssues(changes []git.Commit) []IssueReference {
+	changes = cancelReverts(changes)
+	find := func(repositoryURL string) []IssueReference {
+		var ret []IssueReference
+		seen := map[string]bool{}
+		for i := len(changes) - 1; i >= 0; i-- {
+			for _, issue := range o.issueReferences(changes[i].Message, repositoryURL) {
+				if !seen[issue.key()] {
+					seen[issue.key()] = true
+					ret = append(ret, issue)
+				}
+			}
+		}
+		return ret
+	}
+	ret := find("")
+	if len(ret) == 0 {
+		return nil
+	}
+	return find(o.repositoryURL())
+}
+
+// issueURL returns the link to the issue
+func (p IssuePattern) issueURL(ref IssueReference, repositoryURL string) string {
+	if p.URLTemplate == nil {
+		if repositoryURL == "" {
+			return ""
+		}
+		if strings.Contains(repositoryURL, "gitlab") {
+			return repositoryURL + "/-/issues/" + ref.ID
+		}
+		return repositoryURL + "/issues/" + ref.ID
+	}
+	var sb strings.Builder
+	data := struct{ ID, Ref, RepositoryURL string }{ref.ID, ref.Ref, repositoryURL}
+	if err := p.URLTemplate.Execute(&sb, data); err != nil {
+		log.Warn("%v", newErrorC(err, "Cannot compute the link of issue %s", ref.Ref))
+		return ""
+	}
+	return sb.String()
+}
+
+// repositoryURL returns RepositoryURL or else the web URL of the remote repository, empty if there is none.
+// The web URL of the remote repository is only computed once.
+func (o *BumpStrategy) repositoryURL() string {
+	if o.RepositoryURL != "" {
+		return strings.TrimSuffix(o.RepositoryURL, "/")
+	}
+	if o.remoteWebURL == nil {
+		ret := ""
+		if remote, err := o.gitRepo.GetRemoteURL(); err != nil {
+			log.Debug("%v", newErrorC(err, "Unable to get the remote URL"))
+		} else {
+			ret = webURL(remote)
+		}
+		o.remoteWebURL = &ret
+	}
+	return *o.remoteWebURL
+}
+
+// webURL converts a git remote URL such as git@github.com:owner/repo.git in the web URL of the repository
+// such as https://github.com/owner/repo. It returns an empty string for a local repository.
+func webURL(remote string) string {
+	if m := scpLikeURLRegex.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		remote = "ssh://" + m[1] + "/" + m[2]
+	}
+	u, err := url.Parse(remote)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		return ""
+	}
+	scheme := "https"
+	if u.Scheme == "http" {
+		scheme = "http"
+	}
+	return scheme + "://" + u.Hostname() + strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git")
+}
@@ -0,0 +1,109 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// LintEmptyMessage is the rule violated by an empty commit message
+	LintEmptyMessage = "empty-message"
+	// LintHeaderFormat is the rule violated by a commit header that does not match the CommitPattern
+	LintHeaderFormat = "header-format"
+	// LintHeaderMaxLength is the rule violated by a commit header longer than LintHeaderMaxLengthLimit characters
+	LintHeaderMaxLength = "header-max-length"
+	// LintBodyLeadingBlank is the rule violated by a commit body that is not separated from the header by a blank line
+	LintBodyLeadingBlank = "body-leading-blank"
+
+	// LintHeaderMaxLengthLimit is the maximum length of a commit header
+	LintHeaderMaxLengthLimit = 100
+)
+
+var (
+	// lintIgnoredPattern matches the messages generated by git itself which are not subject to the conventions
+	/* const */ lintIgnoredPattern = regexp.MustCompile(`^(?:Merge |Revert "|fixup! |squash! |amend! )`)
+)
+
+// LintProblem represents a commit message that does not follow the commit conventions
+type LintProblem struct {
+	// Commit is the hash of the commit, it is empty when the message does not come from a commit
+	Commit git.Hash `json:"commit,omitempty"`
+	// Rule is the rule violated: empty-message, header-format, header-max-length or body-leading-blank
+	Rule string `json:"rule"`
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// String returns a string representation of a LintProblem
+func (p LintProblem) String() string {
+	if p.Commit == "" {
+		return fmt.Sprintf("%s: %s", p.Rule, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Commit.Short(), p.Rule, p.Message)
+}
+
+// Lint checks the messages of the commits after from (excluded) up to HEAD.
+// If from is empty, it checks the commits since the last tag.
+func (o *BumpStrategy) Lint(from string) ([]LintProblem, error) {
+	log.Debug("BumpStrategy: lint with configuration: %#v", o)
+
+	if from == "" {
+		lastTag, err := o.gitRepo.GetLastRelativeTag("HEAD")
+		if errors.Is(err, ErrShallowClone) {
+			return nil, newErrorC(err, "Cannot get last relative tag")
+		}
+		if err != nil {
+			// just log for debug, all the commits will be checked
+			log.Debug("%v", newErrorC(err, "Unable to get last relative tag"))
+		}
+		from = lastTag.Name
+	}
+
+	commits, err := o.gitRepo.GetCommits(from, "HEAD")
+	if err != nil {
+		return nil, newErrorC(err, "Cannot get commits from %q", from)
+	}
+
+	problems := []LintProblem{}
+	// report the oldest commit first
+	for i := len(commits) - 1; i >= 0; i-- {
+		for _, p := range o.LintMessage(commits[i].Message) {
+			p.Commit = commits[i].Hash
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// LintMessage checks a commit message and returns all the problems found.
+// Merge, revert, fixup and squash messages generated by git are not checked.
+func (o *BumpStrategy) LintMessage(message string) []LintProblem {
+	problems := []LintProblem{}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return append(problems, LintProblem{Rule: LintEmptyMessage, Message: "commit message is empty"})
+	}
+	if lintIgnoredPattern.MatchString(message) {
+		log.Trace("BumpStrategy: skip lint of %q", message)
+		return problems
+	}
+
+	lines := strings.Split(message, "\n")
+	header := strings.TrimRight(lines[0], "\r")
+	if o.CommitPattern != nil && !o.CommitPattern.MatchString(header) {
+		problems = append(problems, LintProblem{Rule: LintHeaderFormat, Message: fmt.Sprintf("header %q does not match %s", header, o.CommitPattern)})
+	}
+	if length := utf8.RuneCountInString(header); length > LintHeaderMaxLengthLimit {
+		problems = append(problems, LintProblem{Rule: LintHeaderMaxLength, Message: fmt.Sprintf("header is %d characters long, the maximum is %d", length, LintHeaderMaxLengthLimit)})
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, LintProblem{Rule: LintBodyLeadingBlank, Message: "body must be separated from the header by a blank line"})
+	}
+	return problems
+}
@@ -0,0 +1,94 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestLintMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		message  string
+		expected []string
+	}{
+		{"feat: add lint command", []string{}},
+		{"fix(cmd)!: fix flags\n\nBREAKING CHANGE: flags renamed", []string{}},
+		{"  ", []string{LintEmptyMessage}},
+		{"add lint command", []string{LintHeaderFormat}},
+		{"feat:missing space", []string{LintHeaderFormat}},
+		{"feature: add lint command", []string{LintHeaderFormat}},
+		{"feat: " + strings.Repeat("a", 100), []string{LintHeaderMaxLength}},
+		{"feat: " + strings.Repeat("é", 90), []string{}},
+		{"feat: add lint command\nwith a body", []string{LintBodyLeadingBlank}},
+		{"add lint command\nwith a body", []string{LintHeaderFormat, LintBodyLeadingBlank}},
+		{"Merge branch 'feature' into main", []string{}},
+		{"Revert \"feat: add lint command\"\n\nThis reverts commit 1234567890.", []string{}},
+		{"fixup! feat: add lint command", []string{}},
+	}
+
+	strategy := NewConventionalCommitBumpStrategy(nil)
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(t *testing.T) {
+			rules := []string{}
+			for _, p := range strategy.LintMessage(tc.message) {
+				rules = append(rules, p.Rule)
+			}
+			assert.Equal(tc.expected, rules)
+		})
+	}
+}
+
+func TestLintMessageWithoutCommitPattern(t *testing.T) {
+	strategy := &BumpStrategy{}
+	assert.Empty(t, strategy.LintMessage("anything goes"))
+}
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.0.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.0.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("3333333333"), Message: "wip"},
+		{Hash: git.Hash("2222222222"), Message: "fix: typo"},
+		{Hash: git.Hash("1111111111"), Message: "update README.md"},
+	}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	problems, err := strategy.Lint("")
+
+	assert.NoError(err)
+	assert.Len(problems, 2)
+	assert.Equal(git.Hash("1111111111"), problems[0].Commit)
+	assert.Equal(LintHeaderFormat, problems[0].Rule)
+	assert.Equal(git.Hash("3333333333"), problems[1].Commit)
+	assert.Equal(`3333333: header-format: header "wip" does not match ^(?:build|chore|ci|docs|feat|fix|perf|refactor|revert|style|test)(?:\(.+\))?!?: .+`, problems[1].String())
+}
+
+func TestLintFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().GetCommits("origin/main", "HEAD").Times(1).Return(nil, newError("unknown revision"))
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	_, err := strategy.Lint("origin/main")
+
+	assert.EqualError(err, `Cannot get commits from "origin/main" caused by: unknown revision`)
+}
@@ -0,0 +1,41 @@
+package version
+
+import (
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// ReleaseAsToken is the footer token of a commit forcing the next version, eg. "Release-As: 2.0.0"
+	ReleaseAsToken = "Release-As"
+)
+
+// ReleaseAs is the version forced by the Release-As footer of a commit
+type ReleaseAs struct {
+	// Version is the version of the footer
+	Version Version `json:"version"`
+	// Commit is the commit with the footer
+	Commit git.Commit `json:"commit"`
+}
+
+// computeReleaseAs returns the version forced by the most recent commit of the context with a Release-As footer.
+// The commit messages are parsed with the convention of the strategy.
+// It returns nil if there is no such commit.
+func (o *BumpStrategy) computeReleaseAs(context *Context) (*ReleaseAs, error) {
+	for _, commit := range cancelReverts(context.Changes) {
+		value := o.parseMessage(commit.Message).Footer(ReleaseAsToken)
+		if value == "" {
+			continue
+		}
+		next, err := NewVersion(value)
+		if err != nil {
+			return nil, newErrorC(err, "Commit %s has an invalid %s footer", commit.Hash.Short(), ReleaseAsToken)
+		}
+		if !next.GreaterThan(*context.LastVersion) {
+			return nil, newError("Commit %s has a %s footer %v which is not greater than the last version %v", commit.Hash.Short(), ReleaseAsToken, next, context.LastVersion)
+		}
+		log.Debug("BumpStrategy: detects a %s footer with version %v at %#v", ReleaseAsToken, next, commit)
+		return &ReleaseAs{Version: next, Commit: commit}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestBumpVersionStrategyAutoWithReleaseAs(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		messages []string
+		branch   string
+		expected string
+		err      string
+	}{
+		{[]string{"fix: typo", "chore: release 2.0.0\n\nRelease-As: 2.0.0"}, "main", "2.0.0", ""},
+		{[]string{"chore: release 3.0.0\n\nRelease-As: v3.0.0", "chore: release 2.0.0\n\nRelease-As: 2.0.0"}, "main", "3.0.0", ""},
+		{[]string{"chore: release 2.0.0\n\nRelease-As: 2.0.0"}, "feature/test", "1.1.0+1.1234567", ""},
+		{[]string{"chore: release 1.0.0\n\nRelease-As: 1.0.0"}, "main", "", "Commit 1234567 has a Release-As footer 1.0.0 which is not greater than the last version 1.1.0"},
+		{[]string{"chore: release\n\nRelease-As: next"}, "main", "", "Commit 1234567 has an invalid Release-As footer caused by: 'next' is not a semver compatible version"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			commits := make([]git.Commit, len(tc.messages))
+			for i, message := range tc.messages {
+				commits[i] = git.Commit{Hash: git.Hash("1234567890"), Message: message}
+			}
+
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+			gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return(commits, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return(tc.branch, nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			version, err := strategy.Bump()
+
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
+
+func TestBumpVersionStrategyAutoWithReleaseAsAndPreRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("1234567890"), Message: "chore: release 2.0.0\n\nRelease-As: 2.0.0"},
+	}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("milestone-2.0", nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.BumpStrategies = []BumpBranchesStrategy{*NewBumpBranchesStrategy(AUTO, "milestone-2.0", true, "rc", false, "")}
+	version, err := strategy.Bump()
+
+	assert.NoError(err)
+	assert.Equal("2.0.0-rc.0", version.String())
+}
+
+func TestBumpWithContextReleaseAs(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	commit := git.Commit{Hash: git.Hash("1234567890"), Message: "chore: release 2.0.0\n\nRelease-As: 2.0.0"}
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{commit}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	version, context, err := strategy.BumpWithContext()
+	assert.NoError(err)
+	assert.Equal("2.0.0", version.String())
+	assert.Equal(&ReleaseAs{Version: Version{Major: 2}, Commit: commit}, context.ReleaseAs)
+}
+
+func TestBumpVersionStrategyAutoWithReleaseAsAndConvention(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("1234567890"), Message: ":bookmark: release 2.0.0\n\nRelease-As: 2.0.0"},
+	}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+	strategy, err := NewBumpStrategyWithConvention(Gitmoji, gitRepo)
+	assert.NoError(err)
+	version, err := strategy.Bump()
+
+	assert.NoError(err)
+	assert.Equal("2.0.0", version.String())
+}
@@ -0,0 +1,97 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// DefaultReleaseCommitTemplate is the default template of the message of a release commit.
+	// Its data is a Release whose Tag is the tag of the version.
+	DefaultReleaseCommitTemplate = "chore(release): {{.Tag.Name}}"
+)
+
+// releaseCommitVersion is the version the release commit message template is rendered with to create its pattern
+var releaseCommitVersion = Version{Major: 97531, Minor: 86420, Patch: 13579}
+
+const (
+	// versionPattern matches a semver version
+	versionPattern = `\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`
+	// tagPattern matches a tag name ending with a version, like the tag name template requires
+	tagPattern = `(?:\S*/)?v?` + versionPattern
+)
+
+// NewReleaseCommitPattern creates the ReleaseCommitPattern matching exactly the messages the release commit message
+// template renders for any version, so that a commit is ignored only if its whole message is a release commit message.
+// The template must render the version, with the Version or the Tag name of the Release, and nothing else of the Release.
+func NewReleaseCommitPattern(messageTemplate *template.Template) (*regexp.Regexp, error) {
+	version := releaseCommitVersion.String()
+	var sb strings.Builder
+	if err := messageTemplate.Execute(&sb, &Release{Version: releaseCommitVersion, Tag: &git.Tag{Name: "v" + version}}); err != nil {
+		return nil, newErrorC(err, "Cannot render the release commit message template")
+	}
+	message := strings.TrimSpace(sb.String())
+	if !strings.Contains(message, version) {
+		return nil, newError("Release commit message template '%s' does not render the version", utils.TemplateToString(messageTemplate))
+	}
+	pattern := regexp.QuoteMeta(message)
+	pattern = strings.ReplaceAll(pattern, "v"+regexp.QuoteMeta(version), tagPattern)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta(version), versionPattern)
+	ret, err := regexp.Compile(`\A` + pattern + `\s*\z`)
+	if err != nil {
+		return nil, newErrorC(err, "Cannot create the pattern of the release commit message template '%s'", utils.TemplateToString(messageTemplate))
+	}
+	return ret, nil
+}
+
+// ReleaseCommitMessage renders the message of the release commit of a release whose Tag is the tag to create.
+// The message must match ReleaseCommitPattern so that the release commit is ignored to compute the next versions.
+func (o *BumpStrategy) ReleaseCommitMessage(r *Release, messageTemplate *template.Template) (string, error) {
+	var sb strings.Builder
+	if err := messageTemplate.Execute(&sb, r); err != nil {
+		return "", newErrorC(err, "Cannot render the release commit message of %v", r.Version)
+	}
+	message := strings.TrimSpace(sb.String())
+	if o.ReleaseCommitPattern == nil {
+		return "", newError("Release commit message '%s' would not be ignored to compute the next versions, the release commit pattern is not set", message)
+	}
+	if !o.isReleaseCommit(git.Commit{Message: message}) {
+		return "", newError("Release commit message '%s' does not match the release commit pattern '%s'", message, o.ReleaseCommitPattern)
+	}
+	return message, nil
+}
+
+// CommitRelease commits the files updated with the version with the release commit message
+func (o *BumpStrategy) CommitRelease(message string, paths ...string) error {
+	log.Debug("BumpStrategy: commit %v with message %q", paths, message)
+	if err := o.gitRepo.Commit(message, paths...); err != nil {
+		return newErrorC(err, "Cannot commit the release")
+	}
+	return nil
+}
+
+// isReleaseCommit returns true if the commit is a release commit
+func (o *BumpStrategy) isReleaseCommit(commit git.Commit) bool {
+	return matchPattern(o.ReleaseCommitPattern, commit.Message)
+}
+
+// excludeReleaseCommits returns the commits without the release commits
+func (o *BumpStrategy) excludeReleaseCommits(commits []git.Commit) []git.Commit {
+	if o.ReleaseCommitPattern == nil {
+		return commits
+	}
+	var ret []git.Commit
+	for _, commit := range commits {
+		if o.isReleaseCommit(commit) {
+			log.Debug("BumpStrategy: ignore release commit %s", commit.Hash.Short())
+			continue
+		}
+		ret = append(ret, commit)
+	}
+	return ret
+}
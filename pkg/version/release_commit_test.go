@@ -0,0 +1,206 @@
+package version
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestNewReleaseCommitPattern(t *testing.T) {
+	testData := []struct {
+		template string
+		message  string
+		expected bool
+	}{
+		{DefaultReleaseCommitTemplate, "chore(release): v1.2.1", true},
+		{DefaultReleaseCommitTemplate, "chore(release): v1.2.1\n", true},
+		{DefaultReleaseCommitTemplate, "chore(release): tools/v2.0.0-rc.1+build.5", true},
+		{DefaultReleaseCommitTemplate, "chore(release): prepare the next release", false},
+		{DefaultReleaseCommitTemplate, "chore(release): v1.2.1\n\nBREAKING CHANGE: drop v1", false},
+		{DefaultReleaseCommitTemplate, "chore(release)!: v1.2.1", false},
+		{"release {{.Version}} [skip ci]", "release 1.2.1 [skip ci]", true},
+		{"release {{.Version}} [skip ci]", "release 1.2.1 [skip ci] and more", false},
+		{"release {{.Version}} [skip ci]", "release v1.2.1 [skip ci]", false},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.message, func(t *testing.T) {
+			pattern, err := NewReleaseCommitPattern(utils.NewTemplate(tc.template))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, pattern.MatchString(tc.message))
+		})
+	}
+
+	_, err := NewReleaseCommitPattern(utils.NewTemplate("chore: release"))
+	assert.EqualError(t, err, "Release commit message template 'chore: release' does not render the version")
+}
+
+func TestReleaseCommitMessage(t *testing.T) {
+	testData := []struct {
+		template    string
+		expected    string
+		expectedErr string
+	}{
+		{DefaultReleaseCommitTemplate, "chore(release): v1.3.0", ""},
+		{"release {{.Version}}", "release 1.3.0", ""},
+		// the pattern cannot match the issues of any release
+		{"chore(release): {{.Version}}\n\n{{range .Issues}}Closes {{.Ref}}{{end}}\n", "", "Release commit message 'chore(release): 1.3.0\n\nCloses #3' does not match the release commit pattern"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.template, func(t *testing.T) {
+			assert := assert.New(t)
+
+			strategy := NewConventionalCommitBumpStrategy(nil)
+			pattern, err := NewReleaseCommitPattern(utils.NewTemplate(tc.template))
+			assert.NoError(err)
+			strategy.ReleaseCommitPattern = pattern
+			r := &Release{
+				Version: Version{Major: 1, Minor: 3},
+				Tag:     &git.Tag{Name: "v1.3.0"},
+				Issues:  []IssueReference{{Ref: "#3", ID: "3"}},
+			}
+			message, err := strategy.ReleaseCommitMessage(r, utils.NewTemplate(tc.template))
+			if tc.expectedErr != "" {
+				assert.ErrorContains(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, message)
+		})
+	}
+
+	// without pattern, the release commit would be part of the next versions
+	r := &Release{Version: Version{Major: 1, Minor: 3}, Tag: &git.Tag{Name: "v1.3.0"}}
+	_, err := NewConventionalCommitBumpStrategy(nil).ReleaseCommitMessage(r, utils.NewTemplate(DefaultReleaseCommitTemplate))
+	assert.EqualError(t, err, "Release commit message 'chore(release): v1.3.0' would not be ignored to compute the next versions, the release commit pattern is not set")
+}
+
+func TestCommitRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().Commit("chore(release): v1.3.0", "package.json", "Chart.yaml").Times(1).Return(nil)
+	gitRepo.EXPECT().Commit("chore(release): v1.3.0").Times(1).Return(errors.New("nothing to commit"))
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	assert.NoError(strategy.CommitRelease("chore(release): v1.3.0", "package.json", "Chart.yaml"))
+	assert.EqualError(strategy.CommitRelease("chore(release): v1.3.0"), "Cannot commit the release caused by: nothing to commit")
+}
+
+func TestBumpIgnoresReleaseCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the release commit of 1.2.1 has not been tagged
+	commits := []git.Commit{
+		{Hash: git.Hash("2222222222"), Message: "chore(release): v1.2.1"},
+		{Hash: git.Hash("1111111111"), Message: "fix: handle empty body"},
+	}
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return(commits, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.ReleaseCommitPattern = newDefaultReleaseCommitPattern(t)
+	v, err := strategy.Bump()
+	assert.NoError(err)
+	// chore commits bump the minor number with the default patterns
+	assert.Equal("1.2.1", v.String())
+}
+
+func TestBumpKeepsReleaseCommitsByDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// a breaking change written by a person with the scope of the release commits
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("1111111111"), Message: "chore(release): drop the 1.x branches\n\nBREAKING CHANGE: 1.x is not released anymore"},
+	}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	v, err := strategy.Bump()
+	assert.NoError(err)
+	assert.Equal("2.0.0", v.String())
+}
+
+func TestReleaseIgnoresReleaseCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", errors.New("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{{Name: "v1.2.1"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.2.1^").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "v1.2.1").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("2222222222"), Message: "chore(release): v1.2.1"},
+		{Hash: git.Hash("1111111111"), Message: "fix: handle empty body"},
+	}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.ReleaseCommitPattern = newDefaultReleaseCommitPattern(t)
+	r, err := strategy.Release("", "")
+	assert.NoError(err)
+	assert.Len(r.Sections, 1)
+	assert.Equal("Bug Fixes", r.Sections[0].Title)
+	assert.Len(r.Sections[0].Entries, 1)
+}
+
+func TestAuditIgnoresReleaseCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetTags().Times(1).Return([]git.Tag{{Name: "v1.2.0"}, {Name: "v1.3.0"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.2.0^").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.1.0", "v1.2.0").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("2222222222"), Message: "chore(release): v1.2.0"},
+		{Hash: git.Hash("1111111111"), Message: "feat: add endpoint"},
+	}, nil)
+	// a release commit alone does not require a bump
+	gitRepo.EXPECT().GetLastRelativeTag("v1.3.0^").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "v1.3.0").Times(1).Return([]git.Commit{
+		{Hash: git.Hash("3333333333"), Message: "chore(release): v1.3.0"},
+	}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.ReleaseCommitPattern = newDefaultReleaseCommitPattern(t)
+	violations, err := strategy.Audit()
+	assert.NoError(err)
+	assert.Equal([]AuditViolation{
+		{Tag: "v1.3.0", PreviousTag: "v1.2.0", Type: AuditOverBump, Message: "bumps MINOR but there is no commit since 1.2.0"},
+	}, violations)
+}
+
+// newDefaultReleaseCommitPattern creates the release commit pattern of the default release commit message template
+func newDefaultReleaseCommitPattern(t *testing.T) *regexp.Regexp {
+	pattern, err := NewReleaseCommitPattern(utils.NewTemplate(DefaultReleaseCommitTemplate))
+	assert.NoError(t, err)
+	return pattern
+}
@@ -0,0 +1,62 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+var (
+	/* const */ revertHeaderRegex = regexp.MustCompile(`^(?:[Rr]evert(?:\([^()\r\n]*\))?!?: |Revert ")`)
+	/* const */ revertedCommitRegex = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-fA-F]{7,40})\b`)
+)
+
+// revertedHash returns the hash of the commit reverted by commit or an empty string if it is not a revert commit
+func revertedHash(commit git.Commit) string {
+	if !revertHeaderRegex.MatchString(commit.Message) {
+		return ""
+	}
+	if m := revertedCommitRegex.FindStringSubmatch(commit.Message); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return ""
+}
+
+// cancelReverts removes the revert commits and the commits they revert when both are in the list.
+// The commits are expected from the most recent to the oldest, like git log, so a revert of a revert is paired first.
+// All the changes of a reverted commit split by splitCommits are removed.
+func cancelReverts(commits []git.Commit) []git.Commit {
+	cancelled := make(map[int]bool)
+	for i, commit := range commits {
+		hash := revertedHash(commit)
+		if hash == "" || cancelled[i] {
+			continue
+		}
+		var reverted git.Hash
+		for j := i + 1; j < len(commits); j++ {
+			if cancelled[j] {
+				continue
+			}
+			if reverted == "" && strings.HasPrefix(strings.ToLower(commits[j].Hash.String()), hash) {
+				reverted = commits[j].Hash
+			}
+			if reverted != "" && commits[j].Hash == reverted {
+				log.Trace("BumpStrategy: %#v cancels %#v", commit, commits[j])
+				cancelled[i], cancelled[j] = true, true
+			}
+		}
+	}
+
+	if len(cancelled) == 0 {
+		return commits
+	}
+	ret := make([]git.Commit, 0, len(commits)-len(cancelled))
+	for i, commit := range commits {
+		if !cancelled[i] {
+			ret = append(ret, commit)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestCancelReverts(t *testing.T) {
+	assert := assert.New(t)
+
+	feat := git.Commit{Hash: "aaaaaaaaaa", Message: "feat!: remove endpoint"}
+	fix := git.Commit{Hash: "bbbbbbbbbb", Message: "fix: typo"}
+	revertFeat := git.Commit{Hash: "cccccccccc", Message: "revert: feat!: remove endpoint\n\nThis reverts commit aaaaaaaaaa."}
+	gitRevertFeat := git.Commit{Hash: "dddddddddd", Message: "Revert \"feat!: remove endpoint\"\n\nThis reverts commit AAAAAAA."}
+	revertRevert := git.Commit{Hash: "eeeeeeeeee", Message: "Revert \"revert: feat!: remove endpoint\"\n\nThis reverts commit cccccccccc."}
+	revertReleased := git.Commit{Hash: "ffffffffff", Message: "revert: fix: old\n\nThis reverts commit 0123456789."}
+	notARevert := git.Commit{Hash: "1111111111", Message: "docs: explain\n\nThis reverts commit aaaaaaaaaa."}
+	squashFeat := git.Commit{Hash: "2222222222", Message: "Squash feature (#12)"}
+	squashFix := git.Commit{Hash: "2222222222", Message: "fix: b"}
+	revertSquash := git.Commit{Hash: "3333333333", Message: "Revert \"Squash feature (#12)\"\n\nThis reverts commit 2222222222."}
+
+	testData := []struct {
+		commits  []git.Commit
+		expected []git.Commit
+	}{
+		{[]git.Commit{fix, feat}, []git.Commit{fix, feat}},
+		{[]git.Commit{revertFeat, fix, feat}, []git.Commit{fix}},
+		{[]git.Commit{gitRevertFeat, feat}, []git.Commit{}},
+		{[]git.Commit{revertRevert, revertFeat, feat}, []git.Commit{feat}},
+		{[]git.Commit{revertReleased, fix}, []git.Commit{revertReleased, fix}},
+		{[]git.Commit{notARevert, feat}, []git.Commit{notARevert, feat}},
+		// all the changes of a split squash merge commit are cancelled
+		{[]git.Commit{revertSquash, fix, squashFeat, squashFix}, []git.Commit{fix}},
+		// a revert cannot cancel a more recent commit
+		{[]git.Commit{feat, revertFeat}, []git.Commit{feat, revertFeat}},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			assert.Equal(tc.expected, cancelReverts(tc.commits))
+		})
+	}
+}
+
+func TestBumpVersionStrategyAutoWithRevert(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		commits  []git.Commit
+		expected string
+	}{
+		{[]git.Commit{
+			{Hash: "cccccccccc", Message: "revert: feat!: remove endpoint\n\nThis reverts commit aaaaaaaaaa."},
+			{Hash: "bbbbbbbbbb", Message: "feat: add endpoint"},
+			{Hash: "aaaaaaaaaa", Message: "feat!: remove endpoint"},
+		}, "1.2.0"},
+		{[]git.Commit{
+			{Hash: "cccccccccc", Message: "revert: feat!: remove endpoint\n\nThis reverts commit aaaaaaaaaa."},
+			{Hash: "aaaaaaaaaa", Message: "feat!: remove endpoint"},
+		}, "1.1.0"},
+		{[]git.Commit{
+			{Hash: "cccccccccc", Message: "revert: chore: release 2.0.0\n\nThis reverts commit aaaaaaaaaa."},
+			{Hash: "aaaaaaaaaa", Message: "chore: release 2.0.0\n\nRelease-As: 2.0.0"},
+		}, "1.1.0"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+			gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return(tc.commits, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			version, err := strategy.Bump()
+
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
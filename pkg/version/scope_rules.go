@@ -0,0 +1,83 @@
+package version
+
+import (
+	"strings"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+)
+
+// ScopeFilter restricts the conventional commit scopes that can trigger a bump level
+type ScopeFilter struct {
+	// Allow is the list of scopes that can trigger the bump level. If empty, all scopes can.
+	Allow []string `json:"allow,omitempty"`
+	// Deny is the list of scopes that cannot trigger the bump level.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// permits returns true if one of the scopes can trigger the bump level
+func (f ScopeFilter) permits(scopes []string) bool {
+	for _, scope := range scopes {
+		if utils.ContainsString(f.Deny, scope) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if utils.ContainsString(f.Allow, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeRules configures how the conventional commit scopes affect the bump.
+// A commit whose scope is not permitted at its bump level is downgraded to the next permitted level.
+// Commits without scope are not affected by these rules.
+type ScopeRules struct {
+	// Ignore is the list of scopes whose commits never trigger a bump
+	Ignore []string `json:"ignore,omitempty"`
+	// Major restricts the scopes that can trigger a major bump
+	Major ScopeFilter `json:"major,omitempty"`
+	// Minor restricts the scopes that can trigger a minor bump
+	Minor ScopeFilter `json:"minor,omitempty"`
+	// Patch restricts the scopes that can trigger a patch bump
+	Patch ScopeFilter `json:"patch,omitempty"`
+}
+
+// apply returns the bump level permitted for a commit with the scope and the level detected from its message.
+// It returns false if the commit must not trigger any bump.
+func (r ScopeRules) apply(scope string, level BumpStrategyType) (BumpStrategyType, bool) {
+	scopes := splitScope(scope)
+	if len(scopes) == 0 {
+		return level, true
+	}
+
+	ignored := true
+	for _, s := range scopes {
+		ignored = ignored && utils.ContainsString(r.Ignore, s)
+	}
+	if ignored {
+		return level, false
+	}
+
+	filters := map[BumpStrategyType]ScopeFilter{MAJOR: r.Major, MINOR: r.Minor, PATCH: r.Patch}
+	for ; level >= PATCH; level-- {
+		if filters[level].permits(scopes) {
+			return level, true
+		}
+	}
+	return PATCH, false
+}
+
+// splitScope splits a scope such as "api,core" in a list of scopes
+func splitScope(scope string) []string {
+	var scopes []string
+	for _, s := range strings.Split(scope, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,98 @@
+package version
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestScopeRulesApply(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := ScopeRules{
+		Ignore: []string{"deps", "ci"},
+		Major:  ScopeFilter{Allow: []string{"api"}},
+		Minor:  ScopeFilter{Deny: []string{"internal"}},
+		Patch:  ScopeFilter{Deny: []string{"test"}},
+	}
+
+	testData := []struct {
+		scope         string
+		level         BumpStrategyType
+		expectedLevel BumpStrategyType
+		expectedOk    bool
+	}{
+		{"", MAJOR, MAJOR, true},
+		{"api", MAJOR, MAJOR, true},
+		{"core", MAJOR, MINOR, true},
+		{"internal", MAJOR, PATCH, true},
+		{"internal", MINOR, PATCH, true},
+		{"ui", MINOR, MINOR, true},
+		{"deps", MAJOR, MAJOR, false},
+		{"test", PATCH, PATCH, false},
+		{"test", MINOR, MINOR, true},
+		{"deps, ci", PATCH, PATCH, false},
+		{"deps,api", MAJOR, MAJOR, true},
+		{"api,internal", MINOR, PATCH, true},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			level, ok := rules.apply(tc.scope, tc.level)
+			assert.Equal(tc.expectedOk, ok)
+			if ok {
+				assert.Equal(tc.expectedLevel, level)
+			}
+		})
+	}
+}
+
+func TestBumpVersionStrategyAutoWithScopeRules(t *testing.T) {
+	assert := assert.New(t)
+
+	// mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testData := []struct {
+		messages []string
+		expected string
+	}{
+		{[]string{"feat(internal): add helper"}, "1.1.1"},
+		{[]string{"fix(api)!: drop v1", "feat(internal): add helper"}, "2.0.0"},
+		{[]string{"feat(internal)!: rewrite helper"}, "1.1.1"},
+		{[]string{"fix(deps): bump dependencies"}, "1.1.0"},
+		{[]string{"fix(deps): bump dependencies", "feat: add option"}, "1.2.0"},
+	}
+
+	for idx, tc := range testData {
+		t.Run(fmt.Sprintf("Case %d", idx), func(_ *testing.T) {
+			commits := make([]git.Commit, len(tc.messages))
+			for i, message := range tc.messages {
+				commits[i] = git.Commit{Hash: git.Hash("1234567890"), Message: message}
+			}
+
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.1.0"}, nil)
+			gitRepo.EXPECT().GetCommits("v1.1.0", "HEAD").Times(1).Return(commits, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.Scopes = ScopeRules{
+				Ignore: []string{"deps"},
+				Major:  ScopeFilter{Allow: []string{"api"}},
+				Minor:  ScopeFilter{Deny: []string{"internal"}},
+			}
+			version, err := strategy.Bump()
+
+			assert.NoError(err)
+			assert.Equal(tc.expected, version.String())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package version
+
+import (
+	"errors"
+	"strings"
+	"text/template"
+
+	"github.com/arnaud-deprez/gsemver/internal/log"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+)
+
+const (
+	// DefaultTagNameTemplate is the default template of the name of a version tag. Its data is a Release.
+	DefaultTagNameTemplate = "v{{.Version}}"
+	// DefaultTagMessageTemplate is the default template of the message of a version tag. Its data is a Release.
+	DefaultTagMessageTemplate = "Release {{.Version}}"
+	// DefaultPushRetries is the default number of times a tag is computed again when its push is rejected
+	DefaultPushRetries = 3
+)
+
+// TagOptions defines how the tag of the next version is created
+type TagOptions struct {
+	// NameTemplate is the go template of the tag name, its data is a Release
+	NameTemplate *template.Template
+	// MessageTemplate is the go template of the tag message, its data is a Release
+	MessageTemplate *template.Template
+	// Sign signs the tag with the gpg key of the tagger
+	Sign bool
+	// Push pushes the tag to the remote repository
+	Push bool
+	// PushRetries is the number of times the version is computed again when the remote repository rejects the tag
+	PushRetries int
+}
+
+// NextTag computes the tag of the next version of HEAD, the version Bump would compute.
+// The name and the message of the tag are rendered with the templates from the release notes of the version.
+// If HEAD already has a version tag, the tag is nil and the release is the one of this tag.
+func (o *BumpStrategy) NextTag(nameTemplate *template.Template, messageTemplate *template.Template) (*git.Tag, *Release, error) {
+	log.Debug("BumpStrategy: next tag with configuration: %#v", o)
+
+	r, err := o.Release("", "HEAD")
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.Tag != nil {
+		return nil, r, nil
+	}
+	// the version is not bumped, eg. when no bump strategy matches the branch
+	if r.PreviousTag != nil {
+		if v, err := NewVersion(extractVersionFromTag(r.PreviousTag.Name)); err == nil && v.String() == r.Version.String() {
+			return nil, nil, newError("Version %v is already released by tag %s", r.Version, r.PreviousTag.Name)
+		}
+	}
+
+	var name, message strings.Builder
+	if err := nameTemplate.Execute(&name, r); err != nil {
+		return nil, nil, newErrorC(err, "Cannot render the tag name of %v", r.Version)
+	}
+	if err := messageTemplate.Execute(&message, r); err != nil {
+		return nil, nil, newErrorC(err, "Cannot render the tag message of %v", r.Version)
+	}
+
+	tag := &git.Tag{Name: strings.TrimSpace(name.String()), Message: strings.TrimSpace(message.String())}
+	// the version must be found back from the tag name to compute the next versions
+	if v, err := NewVersion(extractVersionFromTag(tag.Name)); err != nil || v.String() != r.Version.String() {
+		return nil, nil, newError("Tag name '%s' does not end with the version %v", tag.Name, r.Version)
+	}
+	if tag.Message == "" {
+		return nil, nil, newError("Tag message of %v is empty", r.Version)
+	}
+	return tag, r, nil
+}
+
+// CreateTag creates the tag as an annotated tag on HEAD. If sign is true, the tag is signed with the gpg key of the tagger.
+func (o *BumpStrategy) CreateTag(tag git.Tag, sign bool) error {
+	if err := o.gitRepo.CreateTag(tag.Name, tag.Message, sign); err != nil {
+		return newErrorC(err, "Cannot create tag %s", tag.Name)
+	}
+	return nil
+}
+
+// ReleaseTag creates the tag of the next version of HEAD and pushes it if Push is true.
+// If HEAD already has a version tag, no tag is created and this tag is pushed.
+//
+// When another tag with the same name has been pushed in the meantime, for instance by a concurrent pipeline, the remote
+// repository rejects the tag. Then the local tag is deleted and the version is computed again, like Bump does, from the
+// fetched tags before tagging and pushing again, up to PushRetries times.
+//
+// It returns the release whose Tag is the tag of HEAD and true if this tag has been created.
+func (o *BumpStrategy) ReleaseTag(opts TagOptions) (*Release, bool, error) {
+	for attempt := 0; ; attempt++ {
+		tag, r, err := o.NextTag(opts.NameTemplate, opts.MessageTemplate)
+		if err != nil {
+			return nil, false, err
+		}
+		created := tag != nil
+		if created {
+			if err := o.CreateTag(*tag, opts.Sign); err != nil {
+				return nil, false, err
+			}
+			r.Tag = tag
+		}
+		if !opts.Push {
+			return r, created, nil
+		}
+
+		err = o.gitRepo.PushTag(r.Tag.Name)
+		if err == nil {
+			return r, created, nil
+		}
+		if !errors.Is(err, ErrTagAlreadyExists) {
+			return nil, false, newErrorC(err, "Cannot push tag %s", r.Tag.Name)
+		}
+		if o.Fetch.Skip {
+			return nil, false, newErrorC(err, "Cannot push tag %s, the version cannot be computed again from the tags of the remote as the fetch is skipped", r.Tag.Name)
+		}
+		if attempt >= opts.PushRetries {
+			return nil, false, newErrorC(err, "Cannot push tag %s after %d attempts", r.Tag.Name, attempt+1)
+		}
+		log.Warn("Tag %s has been pushed in the meantime, computing the version again", r.Tag.Name)
+		// the tag of the remote repository is fetched instead
+		if err := o.gitRepo.DeleteTag(r.Tag.Name); err != nil {
+			return nil, false, newErrorC(err, "Cannot delete tag %s", r.Tag.Name)
+		}
+	}
+}
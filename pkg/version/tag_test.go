@@ -0,0 +1,203 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/git"
+	mock_version "github.com/arnaud-deprez/gsemver/pkg/version/mock"
+)
+
+func TestNextTag(t *testing.T) {
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "feat(api): add endpoint (#3)"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "fix: handle empty body"}
+
+	testData := []struct {
+		name            string
+		nameTemplate    string
+		messageTemplate string
+		expectedTag     *git.Tag
+		expectedErr     string
+	}{
+		{"default", DefaultTagNameTemplate, DefaultTagMessageTemplate, &git.Tag{Name: "v1.3.0", Message: "Release 1.3.0"}, ""},
+		{"module path", "tools/v{{.Version}}", DefaultTagMessageTemplate, &git.Tag{Name: "tools/v1.3.0", Message: "Release 1.3.0"}, ""},
+		{"release notes", "{{.Version}}", `{{range .Sections}}{{.Title}}: {{len .Entries}}{{"\n"}}{{end}}{{range .Issues}}{{.Ref}}{{end}}`,
+			&git.Tag{Name: "1.3.0", Message: "Features: 1\nBug Fixes: 1\n#3"}, ""},
+		{"no version", "release-{{.Version.Major}}", DefaultTagMessageTemplate, nil, "Tag name 'release-1' does not end with the version 1.3.0"},
+		{"empty message", DefaultTagNameTemplate, " ", nil, "Tag message of 1.3.0 is empty"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			gitRepo.EXPECT().GetRemoteURL().Times(1).Return("https://github.com/owner/repo.git", nil)
+			gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{}, nil)
+			gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+			gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("main", nil)
+			gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return([]git.Commit{c2, c1}, nil)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			tag, r, err := strategy.NextTag(utils.NewTemplate(tc.nameTemplate), utils.NewTemplate(tc.messageTemplate))
+			if tc.expectedErr != "" {
+				assert.EqualError(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedTag, tag)
+			assert.Equal("1.3.0", r.Version.String())
+		})
+	}
+}
+
+func TestNextTagAlreadyTagged(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", errors.New("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{{Name: "v1.2.0"}}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("v1.2.0^").Times(1).Return(git.Tag{}, errors.New("no tag"))
+	gitRepo.EXPECT().GetCommits("", "v1.2.0").Times(1).Return([]git.Commit{{Message: "feat: init"}}, nil)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	tag, r, err := strategy.NextTag(utils.NewTemplate(DefaultTagNameTemplate), utils.NewTemplate(DefaultTagMessageTemplate))
+	assert.NoError(err)
+	assert.Nil(tag)
+	assert.Equal(&git.Tag{Name: "v1.2.0"}, r.Tag)
+}
+
+func TestNextTagNotBumped(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", errors.New("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{}, nil)
+	gitRepo.EXPECT().GetLastRelativeTag("HEAD").Times(1).Return(git.Tag{Name: "v1.2.0"}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(1).Return("feature/a", nil)
+	gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Times(1).Return([]git.Commit{{Message: "fix: a"}}, nil)
+
+	// no bump strategy matches the branch
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	strategy.BumpStrategies = []BumpBranchesStrategy{*NewDefaultBumpBranchesStrategy(DefaultReleaseBranchesPattern)}
+	_, _, err := strategy.NextTag(utils.NewTemplate(DefaultTagNameTemplate), utils.NewTemplate(DefaultTagMessageTemplate))
+	assert.EqualError(err, "Version 1.2.0 is already released by tag v1.2.0")
+}
+
+func TestCreateTag(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().CreateTag("v1.3.0", "Release 1.3.0", true).Times(1).Return(nil)
+	gitRepo.EXPECT().CreateTag("v1.2.0", "Release 1.2.0", false).Times(1).Return(errors.New("already exists"))
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	assert.NoError(strategy.CreateTag(git.Tag{Name: "v1.3.0", Message: "Release 1.3.0"}, true))
+	assert.EqualError(strategy.CreateTag(git.Tag{Name: "v1.2.0", Message: "Release 1.2.0"}, false), "Cannot create tag v1.2.0 caused by: already exists")
+}
+
+func TestReleaseTagPushRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c1 := git.Commit{Hash: git.Hash("1111111111"), Message: "fix: handle empty body"}
+	c2 := git.Commit{Hash: git.Hash("2222222222"), Message: "fix: another fix"}
+	rejected := fmt.Errorf("%w: rejected", ErrTagAlreadyExists)
+
+	gitRepo := mock_version.NewMockGitRepo(ctrl)
+	gitRepo.EXPECT().FetchTags().Times(2).Return(nil)
+	gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", errors.New("no remote"))
+	gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(2).Return([]git.Tag{}, nil)
+	gitRepo.EXPECT().GetCurrentBranch().Times(2).Return("main", nil)
+	gomock.InOrder(
+		// the first attempt computes 1.2.1 from v1.2.0
+		gitRepo.EXPECT().GetLastRelativeTag("HEAD").Return(git.Tag{Name: "v1.2.0"}, nil),
+		gitRepo.EXPECT().GetCommits("v1.2.0", "HEAD").Return([]git.Commit{c2, c1}, nil),
+		gitRepo.EXPECT().CreateTag("v1.2.1", "Release 1.2.1", false).Return(nil),
+		gitRepo.EXPECT().PushTag("v1.2.1").Return(rejected),
+		gitRepo.EXPECT().DeleteTag("v1.2.1").Return(nil),
+		// v1.2.1 has been pushed on c1 in the meantime
+		gitRepo.EXPECT().GetLastRelativeTag("HEAD").Return(git.Tag{Name: "v1.2.1"}, nil),
+		gitRepo.EXPECT().GetCommits("v1.2.1", "HEAD").Return([]git.Commit{c2}, nil),
+		gitRepo.EXPECT().CreateTag("v1.2.2", "Release 1.2.2", false).Return(nil),
+		gitRepo.EXPECT().PushTag("v1.2.2").Return(nil),
+	)
+
+	strategy := NewConventionalCommitBumpStrategy(gitRepo)
+	r, created, err := strategy.ReleaseTag(TagOptions{
+		NameTemplate:    utils.NewTemplate(DefaultTagNameTemplate),
+		MessageTemplate: utils.NewTemplate(DefaultTagMessageTemplate),
+		Push:            true,
+		PushRetries:     DefaultPushRetries,
+	})
+	assert.NoError(err)
+	assert.True(created)
+	assert.Equal(&git.Tag{Name: "v1.2.2", Message: "Release 1.2.2"}, r.Tag)
+	assert.Equal("1.2.2", r.Version.String())
+}
+
+func TestReleaseTagPushErrors(t *testing.T) {
+	testData := []struct {
+		name        string
+		retries     int
+		skipFetch   bool
+		pushErr     error
+		expectedErr string
+	}{
+		{"no retry", 0, false, fmt.Errorf("%w: rejected", ErrTagAlreadyExists), "Cannot push tag v1.2.0 after 1 attempts caused by: tag already exists in the remote repository: rejected"},
+		{"other error", 3, false, errors.New("network error"), "Cannot push tag v1.2.0 caused by: network error"},
+		{"fetch skipped", 3, true, fmt.Errorf("%w: rejected", ErrTagAlreadyExists), "Cannot push tag v1.2.0, the version cannot be computed again from the tags of the remote as the fetch is skipped caused by: tag already exists in the remote repository: rejected"},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// HEAD is already tagged, so the tag is pushed without being created
+			gitRepo := mock_version.NewMockGitRepo(ctrl)
+			if !tc.skipFetch {
+				gitRepo.EXPECT().FetchTags().Times(1).Return(nil)
+			}
+			gitRepo.EXPECT().GetRemoteURL().Times(1).Return("", errors.New("no remote"))
+			gitRepo.EXPECT().GetTagsPointingAt("HEAD").Times(1).Return([]git.Tag{{Name: "v1.2.0"}}, nil)
+			gitRepo.EXPECT().GetLastRelativeTag("v1.2.0^").Times(1).Return(git.Tag{}, errors.New("no tag"))
+			gitRepo.EXPECT().GetCommits("", "v1.2.0").Times(1).Return([]git.Commit{{Message: "feat: init"}}, nil)
+			gitRepo.EXPECT().PushTag("v1.2.0").Times(1).Return(tc.pushErr)
+
+			strategy := NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.Fetch.Skip = tc.skipFetch
+			_, _, err := strategy.ReleaseTag(TagOptions{
+				NameTemplate:    utils.NewTemplate(DefaultTagNameTemplate),
+				MessageTemplate: utils.NewTemplate(DefaultTagMessageTemplate),
+				Push:            true,
+				PushRetries:     tc.retries,
+			})
+			assert.EqualError(err, tc.expectedErr)
+		})
+	}
+}
@@ -186,6 +186,32 @@ func (v Version) PreReleaseIdentifiersEqual(identifiers string) bool {
 		(len(currentIdentifiers) > 0 && utils.ArrayStringEqual(currentIdentifiers[:len(currentIdentifiers)-1], desiredIdentifiers))
 }
 
+// Compare compares the precedence of 2 versions according to https://semver.org/#spec-item-11.
+// It returns -1 if v < o, 0 if v == o and 1 if v > o. Build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	// a pre-release version has a lower precedence than the associated normal version
+	if v.PreRelease == o.PreRelease {
+		return 0
+	}
+	if v.PreRelease == "" {
+		return 1
+	}
+	if o.PreRelease == "" {
+		return -1
+	}
+	return comparePreRelease(extractIdentifiers(v.PreRelease), extractIdentifiers(o.PreRelease))
+}
+
+// GreaterThan returns true if v has a higher precedence than o
+func (v Version) GreaterThan(o Version) bool {
+	return v.Compare(o) > 0
+}
+
 // WithBuildMetadata return a new Version with build metadata
 func (v Version) WithBuildMetadata(metadata string) Version {
 	next := v
@@ -200,3 +226,36 @@ func extractIdentifiers(value string) []string {
 	}
 	return strings.Split(value, ".")
 }
+
+func comparePreRelease(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		ai, aErr := strconv.Atoi(a[i])
+		bi, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			return sign(ai - bi)
+		// numeric identifiers always have lower precedence than alphanumeric identifiers
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			return sign(strings.Compare(a[i], b[i]))
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+func sign(value int) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -277,3 +277,38 @@ func ExampleVersion_WithBuildMetadata() {
 	fmt.Println(v2.String())
 	// Output: 1.0.0+build.1
 }
+
+func TestVersionCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	testData := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"2.0.0", "1.9.9", 1},
+		{"1.2.0", "1.10.0", -1},
+		{"1.0.1", "1.0.0", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		// example from https://semver.org/#spec-item-11
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-beta.2", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+	}
+
+	for _, tc := range testData {
+		v1, err := NewVersion(tc.v1)
+		assert.NoError(err)
+		v2, err := NewVersion(tc.v2)
+		assert.NoError(err)
+		assert.Equal(tc.expected, v1.Compare(v2), "%s compared to %s", tc.v1, tc.v2)
+		assert.Equal(-tc.expected, v2.Compare(v1), "%s compared to %s", tc.v2, tc.v1)
+		assert.Equal(tc.expected > 0, v1.GreaterThan(v2))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/arnaud-deprez/gsemver/internal/git"
 	"github.com/arnaud-deprez/gsemver/pkg/version"
@@ -22,14 +23,15 @@ var (
 	bumper *version.BumpStrategy
 )
 
-func beforeAll(t *testing.T) {
-	t.Log("BeforeAll: initializing git repo at", GitRepoPath)
+func beforeAll(t *testing.T, backend string) {
+	t.Log("BeforeAll: initializing git repo at", GitRepoPath, "with the git backend", backend)
 	assert.NoError(t, os.RemoveAll(GitRepoPath))
 	os.MkdirAll(GitRepoPath, 0755)
 	execInGitRepo(t, "git init")
 	execInGitRepo(t, "git branch -m main")
 	execInGitRepo(t, "git status")
-	gitRepo := git.NewVersionGitRepo(GitRepoPath)
+	gitRepo, err := git.NewVersionGitRepoWithBackend(backend, GitRepoPath, version.FetchOptions{})
+	assert.NoError(t, err)
 	bumper = version.NewConventionalCommitBumpStrategy(gitRepo)
 }
 
@@ -53,7 +55,16 @@ func TestSuite(t *testing.T) {
 		t.Skip()
 	}
 
-	beforeAll(t)
+	// the git backends must give the same results
+	for _, backend := range git.Backends() {
+		t.Run(backend, func(t *testing.T) {
+			runSuite(t, backend)
+		})
+	}
+}
+
+func runSuite(t *testing.T, backend string) {
+	beforeAll(t, backend)
 
 	tests := []func(t *testing.T){
 		testFirstVersionWithoutCommit,
@@ -68,6 +79,8 @@ func TestSuite(t *testing.T) {
 		testMergeReleaseBranch,
 		testCreateFixPullRequestInReleaseBranch,
 		testMerge2ReleaseBranch,
+		testHistoryMatchesTags,
+		testAuditTags,
 	}
 
 	for _, tf := range tests {
@@ -295,3 +308,23 @@ func testMerge2ReleaseBranch(t *testing.T) {
 	assert.Equal(`1.2.2`, v.String())
 	createTag(t, v.String())
 }
+
+func testHistoryMatchesTags(t *testing.T) {
+	assert := assert.New(t)
+
+	entries, err := bumper.History("")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, e := range entries {
+		assert.True(e.TagsMatch(), "computed version %v does not match tags %v of %s", e.Version, e.Tags, e.Commit.Message)
+	}
+	assert.Equal("1.2.2", entries[len(entries)-1].Version.String())
+}
+
+func testAuditTags(t *testing.T) {
+	assert := assert.New(t)
+
+	violations, err := bumper.Audit()
+	assert.NoError(err)
+	assert.Empty(violations)
+}
@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/git"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+func TestBumpBareRepository(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dir := t.TempDir()
+	remote, work := filepath.Join(dir, "remote.git"), filepath.Join(dir, "work")
+	gitIn := func(dir, args string) string {
+		return execInDir(t, dir, "git -c user.name=gsemver -c user.email=gsemver@example.com "+args)
+	}
+
+	execInDir(t, dir, "git init --bare --initial-branch main "+remote)
+	execInDir(t, dir, "git clone "+remote+" "+work)
+	gitIn(work, `commit --allow-empty -m "feat: initial feature"`)
+	gitIn(work, `tag -a v1.0.0 -m "Release 1.0.0"`)
+	gitIn(work, `commit --allow-empty -m "fix: first fix"`)
+	gitIn(work, "push origin HEAD:refs/heads/main v1.0.0")
+
+	for _, backend := range git.Backends() {
+		t.Run(backend, func(t *testing.T) {
+			assert := assert.New(t)
+			gitRepo, err := git.NewVersionGitRepoWithBackend(backend, remote, version.FetchOptions{Skip: true})
+			assert.NoError(err)
+			strategy := version.NewConventionalCommitBumpStrategy(gitRepo)
+			strategy.Fetch.Skip = true
+
+			// a bare repository has no working tree, it is only queried if the dirty policy does not ignore it
+			v, err := strategy.Bump()
+			assert.NoError(err)
+			assert.Equal("1.0.1", v.String())
+
+			strategy.BumpStrategies[0].DirtyPolicy = version.DirtyFail
+			_, err = strategy.Bump()
+			assert.ErrorContains(err, "Cannot get the status of the working tree")
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arnaud-deprez/gsemver/internal/git"
+	"github.com/arnaud-deprez/gsemver/internal/utils"
+	"github.com/arnaud-deprez/gsemver/pkg/version"
+)
+
+// racingGitRepo runs a concurrent release right after the tags are fetched for the first time
+type racingGitRepo struct {
+	version.GitRepo
+	race func()
+}
+
+func (g *racingGitRepo) FetchTags() error {
+	err := g.GitRepo.FetchTags()
+	if g.race != nil {
+		g.race()
+		g.race = nil
+	}
+	return err
+}
+
+func TestReleasePushRetry(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	remote, pipeline1, pipeline2 := filepath.Join(dir, "remote.git"), filepath.Join(dir, "pipeline1"), filepath.Join(dir, "pipeline2")
+	gitIn := func(dir, args string) string {
+		return execInDir(t, dir, "git -c user.name=gsemver -c user.email=gsemver@example.com "+args)
+	}
+
+	execInDir(t, dir, "git init --bare "+remote)
+	execInDir(t, dir, "git clone "+remote+" "+pipeline1)
+	gitIn(pipeline1, `commit --allow-empty -m "feat: initial feature"`)
+	gitIn(pipeline1, `tag -a v1.0.0 -m "Release 1.0.0"`)
+	gitIn(pipeline1, `commit --allow-empty -m "fix: first fix"`)
+	gitIn(pipeline1, "push origin HEAD:refs/heads/main v1.0.0")
+
+	// the second pipeline builds a more recent commit of main
+	execInDir(t, dir, "git clone --branch main "+remote+" "+pipeline2)
+	gitIn(pipeline2, `commit --allow-empty -m "fix: second fix"`)
+	gitIn(pipeline2, "push origin HEAD:main")
+
+	gitRepo := &racingGitRepo{
+		GitRepo: git.NewVersionGitRepo(pipeline2),
+		race: func() {
+			// the first pipeline pushes the same version in the meantime
+			gitIn(pipeline1, `tag -a v1.0.1 -m "Release 1.0.1"`)
+			gitIn(pipeline1, "push --atomic origin v1.0.1")
+		},
+	}
+	t.Setenv("GIT_COMMITTER_NAME", "gsemver")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gsemver@example.com")
+	strategy := version.NewConventionalCommitBumpStrategy(gitRepo)
+	opts := version.TagOptions{
+		NameTemplate:    utils.NewTemplate(version.DefaultTagNameTemplate),
+		MessageTemplate: utils.NewTemplate(version.DefaultTagMessageTemplate),
+		Push:            true,
+		PushRetries:     version.DefaultPushRetries,
+	}
+
+	r, created, err := strategy.ReleaseTag(opts)
+	assert.NoError(err)
+	assert.True(created)
+	assert.Equal("v1.0.2", r.Tag.Name)
+	assert.Equal(gitIn(pipeline2, "rev-parse HEAD"), gitIn(remote, "rev-parse v1.0.2^{commit}"))
+	assert.Equal(gitIn(pipeline1, "rev-parse HEAD"), gitIn(remote, "rev-parse v1.0.1^{commit}"))
+
+	// it can be run again once released
+	r, created, err = strategy.ReleaseTag(opts)
+	assert.NoError(err)
+	assert.False(created)
+	assert.Equal("v1.0.2", r.Tag.Name)
+}